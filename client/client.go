@@ -0,0 +1,164 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+const defaultUserAgent = "grafana-adaptive-metrics-go-client"
+
+// Client is a Grafana Cloud API client.
+type Client struct {
+	Cfg     *Config
+	BaseURL url.URL
+	client  *http.Client
+
+	// This mutex is necessary for now because the API does not support
+	// concurrent writes to the segments endpoint.
+	segmentMutex *sync.Mutex
+}
+
+// Config contains client configuration.
+type Config struct {
+	// APIKey is an optional API key or service account token.
+	APIKey string
+	// HTTPHeaders are optional HTTP headers.
+	HTTPHeaders map[string]string
+	HttpClient  *http.Client
+
+	// Logger receives the client's log entries. Nil disables logging.
+	Logger Logger
+	// Debug enables logging of request headers and request and response
+	// bodies, with credentials redacted.
+	Debug bool
+	// DebugBodyMaxBytes is how much of each body is logged in debug mode.
+	// Defaults to DefaultDebugBodyMaxBytes.
+	DebugBodyMaxBytes int
+
+	UserAgent string
+}
+
+// New creates a new Grafana client.
+func New(baseURL string, cfg *Config) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.HttpClient == nil {
+		cfg.HttpClient = cleanhttp.DefaultClient()
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = defaultUserAgent
+	}
+
+	return &Client{
+		Cfg:     cfg,
+		BaseURL: *u,
+		client:  cfg.HttpClient,
+
+		segmentMutex: &sync.Mutex{},
+	}, nil
+}
+
+func (c *Client) request(ctx context.Context, method, requestPath string, query url.Values, body []byte, responseStruct interface{}) error {
+	_, err := c.requestWithHeaders(ctx, method, requestPath, query, nil, body, responseStruct)
+	return err
+}
+
+// replaceRequest sends a write that fully replaces a resource, so that it can
+// be resent freely whatever its method.
+func (c *Client) replaceRequest(ctx context.Context, method, requestPath string, query url.Values, body []byte, responseStruct interface{}) error {
+	_, err := c.requestWithClass(ctx, classIdempotent, method, requestPath, query, nil, body, responseStruct)
+	return err
+}
+
+func (c *Client) requestWithHeaders(ctx context.Context, method, requestPath string, query url.Values, header http.Header, body []byte, responseStruct interface{}) (http.Header, error) {
+	return c.requestWithClass(ctx, classifyRequest(method, header), method, requestPath, query, header, body, responseStruct)
+}
+
+func (c *Client) requestWithClass(ctx context.Context, class requestClass, method, requestPath string, query url.Values, header http.Header, body []byte, responseStruct interface{}) (http.Header, error) {
+	ctx = c.logContext(ctx)
+	req, err := c.newRequest(withRequestClass(ctx, class), method, requestPath, query, header, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	logFields := c.logRequest(ctx, method, requestPath, query, req.Header, body)
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.logRequestError(ctx, logFields, err, time.Since(start))
+		if class != classIdempotent && ctx.Err() == nil && !isConnectError(err) {
+			return nil, ErrAmbiguousWrite{Method: method, Path: requestPath, Err: err}
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyContents, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logResponse(ctx, logFields, resp.StatusCode, bodyContents, time.Since(start))
+
+	// check status code.
+	if resp.StatusCode >= 400 {
+		err := newStatusError(resp, bodyContents)
+		if class != classIdempotent && resp.StatusCode >= 500 {
+			return nil, ErrAmbiguousWrite{Method: method, Path: requestPath, Err: err}
+		}
+		return nil, err
+	}
+
+	if responseStruct == nil {
+		return resp.Header, nil
+	}
+
+	err = json.Unmarshal(bodyContents, responseStruct)
+	if err != nil {
+		return resp.Header, err
+	}
+
+	return resp.Header, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, requestPath string, query url.Values, header http.Header, body io.Reader) (*http.Request, error) {
+	u := c.BaseURL
+	u.Path = path.Join(u.Path, requestPath)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return req, err
+	}
+
+	if c.Cfg.APIKey != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Cfg.APIKey))
+	}
+
+	if c.Cfg.HTTPHeaders != nil {
+		for k, v := range c.Cfg.HTTPHeaders {
+			req.Header.Add(k, v)
+		}
+	}
+
+	for k, vals := range header {
+		for _, v := range vals {
+			req.Header.Add(k, v)
+		}
+	}
+
+	req.Header.Add("User-Agent", c.Cfg.UserAgent)
+	req.Header.Add("Content-Type", "application/json")
+	return req, err
+}
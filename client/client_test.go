@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"testing"
@@ -8,7 +9,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 var (
@@ -89,13 +90,13 @@ func TestClientAuths(t *testing.T) {
 	cAPI, err := New(s.server.URL, &Config{APIKey: "apikey"})
 	require.NoError(t, err)
 
-	_, err = cAPI.AggregationRecommendationsConfig()
+	_, err = cAPI.AggregationRecommendationsConfig(context.Background())
 	require.NoError(t, err)
 
 	cScope, err := New(s.server.URL, &Config{HTTPHeaders: map[string]string{"x-scope-orgid": "9960"}})
 	require.NoError(t, err)
 
-	_, err = cScope.AggregationRecommendationsConfig()
+	_, err = cScope.AggregationRecommendationsConfig(context.Background())
 	require.NoError(t, err)
 }
 
@@ -110,7 +111,7 @@ func TestAggregationRecommendations(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.AggregationRecommendations("", false, nil)
+	actual, err := c.AggregationRecommendations(context.Background(), "", false, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, recsPayload, actual)
@@ -128,7 +129,7 @@ func TestAggregationSegmentedRecommendations(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.AggregationRecommendations("segment-id", true, nil)
+	actual, err := c.AggregationRecommendations(context.Background(), "segment-id", true, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, verboseRecsPayload, actual)
@@ -146,7 +147,7 @@ func TestAggregationVerboseRecommendations(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.AggregationRecommendations("", true, nil)
+	actual, err := c.AggregationRecommendations(context.Background(), "", true, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, verboseRecsPayload, actual)
@@ -164,7 +165,7 @@ func TestAggregationRecommendationsWithAction(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.AggregationRecommendations("", false, []string{"add", "update"})
+	actual, err := c.AggregationRecommendations(context.Background(), "", false, []string{"add", "update"})
 	require.NoError(t, err)
 
 	require.Equal(t, recsPayload, actual)
@@ -181,7 +182,7 @@ func TestUpdateAggregationRecommendationsConfig(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	require.NoError(t, c.UpdateAggregationRecommendationsConfig(model.AggregationRecommendationConfiguration{
+	require.NoError(t, c.UpdateAggregationRecommendationsConfig(context.Background(), model.AggregationRecommendationConfiguration{
 		KeepLabels: []string{"namespace"},
 	}))
 }
@@ -197,7 +198,7 @@ func TestAggregationRecommendationsConfig(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.AggregationRecommendationsConfig()
+	actual, err := c.AggregationRecommendationsConfig(context.Background())
 	require.NoError(t, err)
 
 	require.Equal(t, model.AggregationRecommendationConfiguration{
@@ -216,7 +217,7 @@ func TestAggregationRules(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actualRules, err := c.SegmentedAggregationRules()
+	actualRules, err := c.SegmentedAggregationRules(context.Background())
 	require.NoError(t, err)
 
 	require.Equal(t, rulesPayload, actualRules[0].Rules)
@@ -239,7 +240,7 @@ func TestReadAggregationRuleSet(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, newEtag, err := c.ReadAggregationRuleSet("segment-id")
+	actual, newEtag, err := c.ReadAggregationRuleSet(context.Background(), "segment-id")
 	require.NoError(t, err)
 
 	require.Equal(t, etag, newEtag)
@@ -267,7 +268,7 @@ func TestUpdateAggregationRuleSet(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	newEtag, err := c.UpdateAggregationRuleSet("segment-id", []model.AggregationRule{{Metric: "test_metric", Drop: true}}, etag)
+	newEtag, err := c.UpdateAggregationRuleSet(context.Background(), "segment-id", []model.AggregationRule{{Metric: "test_metric", Drop: true}}, etag)
 	require.NoError(t, err)
 
 	require.Equal(t, "\"updated-fake-etag\"", newEtag)
@@ -294,7 +295,7 @@ func TestUpdateAggregationRuleSetWithNilRules(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	newEtag, err := c.UpdateAggregationRuleSet("segment-id", nil, etag)
+	newEtag, err := c.UpdateAggregationRuleSet(context.Background(), "segment-id", nil, etag)
 	require.NoError(t, err)
 
 	require.Equal(t, "\"updated-fake-etag\"", newEtag)
@@ -321,7 +322,7 @@ func TestCreateAggregationRule(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	newEtag, err := c.CreateAggregationRule("segment-id", model.AggregationRule{Metric: "test_metric", Drop: true}, etag)
+	newEtag, err := c.CreateAggregationRule(context.Background(), "segment-id", model.AggregationRule{Metric: "test_metric", Drop: true}, etag)
 	require.NoError(t, err)
 
 	require.Equal(t, "\"updated-fake-etag\"", newEtag)
@@ -344,7 +345,7 @@ func TestReadAggregationRule(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, newEtag, err := c.ReadAggregationRule("segment-id", "test_metric")
+	actual, newEtag, err := c.ReadAggregationRule(context.Background(), "segment-id", "test_metric")
 	require.NoError(t, err)
 
 	require.Equal(t, etag, newEtag)
@@ -372,7 +373,7 @@ func TestUpdateAggregationRule(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	newEtag, err := c.UpdateAggregationRule("segment-id", model.AggregationRule{Metric: "test_metric", Drop: true}, etag)
+	newEtag, err := c.UpdateAggregationRule(context.Background(), "segment-id", model.AggregationRule{Metric: "test_metric", Drop: true}, etag)
 	require.NoError(t, err)
 
 	require.Equal(t, "\"updated-fake-etag\"", newEtag)
@@ -398,7 +399,7 @@ func TestDeleteAggregationRule(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	newEtag, err := c.DeleteAggregationRule("segment-id", "test_metric", etag)
+	newEtag, err := c.DeleteAggregationRule(context.Background(), "segment-id", "test_metric", etag)
 	require.NoError(t, err)
 
 	require.Equal(t, "\"updated-fake-etag\"", newEtag)
@@ -420,7 +421,7 @@ func TestCreateExemption(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.CreateExemption("segment-id", model.Exemption{
+	actual, err := c.CreateExemption(context.Background(), "segment-id", model.Exemption{
 		Metric:     "test_metric",
 		KeepLabels: []string{"foobar"},
 	})
@@ -437,6 +438,42 @@ func TestCreateExemption(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestListExemptions(t *testing.T) {
+	s := newMockServer(t)
+	defer s.close()
+
+	respBody := []byte(`{"result":[{"id":"ulid-1","metric":"test_metric","keep_labels":["foobar"],"managed_by":"terraform","reason":"needed","created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z"},{"id":"ulid-2","metric":"other_metric","disable_recommendations":true,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}]}`)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	expected := []model.Exemption{
+		{
+			ID:         "ulid-1",
+			Metric:     "test_metric",
+			KeepLabels: []string{"foobar"},
+			ManagedBy:  "terraform",
+			Reason:     "needed",
+			CreatedAt:  created,
+			UpdatedAt:  created,
+		},
+		{
+			ID:                     "ulid-2",
+			Metric:                 "other_metric",
+			DisableRecommendations: true,
+		},
+	}
+
+	s.addExpected("GET", "/v1/recommendations/exemptions",
+		withRespBody(respBody),
+		withParams(url.Values{"segment": []string{"segment-id"}}),
+	)
+
+	c, err := New(s.server.URL, &Config{})
+	require.NoError(t, err)
+
+	actual, err := c.ListExemptions(context.Background(), "segment-id")
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
 func TestReadExemption(t *testing.T) {
 	s := newMockServer(t)
 	defer s.close()
@@ -458,7 +495,7 @@ func TestReadExemption(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.ReadExemption("segment-id", "generated-ulid")
+	actual, err := c.ReadExemption(context.Background(), "segment-id", "generated-ulid")
 	require.NoError(t, err)
 
 	require.Equal(t, expected, actual)
@@ -478,7 +515,7 @@ func TestUpdateExemption(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	err = c.UpdateExemption("segment-id", model.Exemption{
+	err = c.UpdateExemption(context.Background(), "segment-id", model.Exemption{
 		ID:         "generated-ulid",
 		Metric:     "test_metric",
 		KeepLabels: []string{"foobar"},
@@ -497,7 +534,7 @@ func TestDeleteExemption(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	err = c.DeleteExemption("segment-id", "generated-ulid")
+	err = c.DeleteExemption(context.Background(), "segment-id", "generated-ulid")
 	require.NoError(t, err)
 }
 
@@ -516,7 +553,7 @@ func TestCreateSegment(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.CreateSegment(model.Segment{
+	actual, err := c.CreateSegment(context.Background(), model.Segment{
 		Name:              "segment name",
 		Selector:          "{foo=\"bar\"}",
 		FallbackToDefault: true,
@@ -552,7 +589,7 @@ func TestReadSegment(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	actual, err := c.ReadSegment("generated-ulid")
+	actual, err := c.ReadSegment(context.Background(), "generated-ulid")
 	require.NoError(t, err)
 
 	require.Equal(t, expected, actual)
@@ -572,7 +609,7 @@ func TestUpdateSegment(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	err = c.UpdateSegment(model.Segment{
+	err = c.UpdateSegment(context.Background(), model.Segment{
 		ID:                "generated-ulid",
 		Name:              "segment name",
 		Selector:          "{foo=\"bar\"}",
@@ -592,6 +629,20 @@ func TestDeleteSegment(t *testing.T) {
 	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
 
-	err = c.DeleteSegment("generated-ulid")
+	err = c.DeleteSegment(context.Background(), "generated-ulid")
+	require.NoError(t, err)
+}
+
+func TestRequestHonorsContextCancellation(t *testing.T) {
+	s := newMockServer(t)
+	defer s.close()
+
+	c, err := New(s.server.URL, &Config{})
 	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.AggregationRecommendationsConfig(ctx)
+	require.ErrorIs(t, err, context.Canceled)
 }
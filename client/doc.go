@@ -0,0 +1,19 @@
+// Package client is a Go client for the Grafana Cloud Adaptive Metrics API.
+//
+// It is the client the Terraform provider uses, and can be imported by other
+// Go programs that need to manage aggregation rules, segments, exemptions and
+// recommendations. New takes the tenant's Adaptive Metrics URL, the same URL
+// as the provider's url attribute; see the package example.
+//
+// Segment IDs are empty for the default segment. Writes to aggregation rules
+// are guarded by the ETag returned by the last read or write of the segment's
+// rules; a stale ETag fails with ErrPreconditionFailed.
+//
+// Unsuccessful responses are returned as typed errors, such as ErrNotFound,
+// ErrValidation or ErrRateLimited, which can be tested for with the IsErr
+// functions or errors.As. The request and response types live in the model
+// package.
+//
+// Requests are logged through the Logger set in Config, if any, with
+// credentials redacted.
+package client
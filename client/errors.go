@@ -0,0 +1,226 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newStatusError maps an unsuccessful response to the matching typed error.
+func newStatusError(resp *http.Response, bodyContents []byte) error {
+	switch code := resp.StatusCode; {
+	case code == http.StatusNotFound:
+		return ErrNotFound{BodyContents: bodyContents}
+	case code == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed{BodyContents: bodyContents}
+	case code == http.StatusUnauthorized:
+		return ErrUnauthorized{BodyContents: bodyContents}
+	case code == http.StatusForbidden:
+		return ErrForbidden{BodyContents: bodyContents}
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+		return ErrValidation{
+			StatusCode:   code,
+			Message:      parseErrorMessage(bodyContents),
+			BodyContents: bodyContents,
+		}
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited{
+			RetryAfter:   parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			BodyContents: bodyContents,
+		}
+	case code >= 500:
+		return ErrServerError{StatusCode: code, BodyContents: bodyContents}
+	default:
+		return ErrUnexpectedStatus{StatusCode: code, BodyContents: bodyContents}
+	}
+}
+
+// parseErrorMessage extracts the human readable message from an error
+// response body. The API usually responds with a JSON object carrying the
+// message, but proxies in front of it may respond with plain text.
+func parseErrorMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Message != "" {
+			return payload.Message
+		}
+		if payload.Error != "" {
+			return payload.Error
+		}
+	}
+
+	return strings.TrimSpace(string(body))
+}
+
+// parseRetryAfter parses a Retry-After header value, which may either be a
+// number of seconds or an HTTP date. It returns zero if the header is absent
+// or malformed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+func IsErrNotFound(err error) bool {
+	var e ErrNotFound
+	return errors.As(err, &e)
+}
+
+func IsErrPreconditionFailed(err error) bool {
+	var e ErrPreconditionFailed
+	return errors.As(err, &e)
+}
+
+func IsErrUnauthorized(err error) bool {
+	var e ErrUnauthorized
+	return errors.As(err, &e)
+}
+
+func IsErrForbidden(err error) bool {
+	var e ErrForbidden
+	return errors.As(err, &e)
+}
+
+func IsErrValidation(err error) bool {
+	var e ErrValidation
+	return errors.As(err, &e)
+}
+
+func IsErrRateLimited(err error) bool {
+	var e ErrRateLimited
+	return errors.As(err, &e)
+}
+
+func IsErrServerError(err error) bool {
+	var e ErrServerError
+	return errors.As(err, &e)
+}
+
+func IsErrAmbiguousWrite(err error) bool {
+	var e ErrAmbiguousWrite
+	return errors.As(err, &e)
+}
+
+// ErrNotFound is returned when the requested object does not exist (404).
+type ErrNotFound struct {
+	BodyContents []byte
+}
+
+func (e ErrNotFound) Error() string {
+	return fmt.Sprintf("status: 404, body: %s", e.BodyContents)
+}
+
+// ErrPreconditionFailed is returned when a write was sent with an If-Match
+// ETag that no longer matches the segment's current rules (412).
+type ErrPreconditionFailed struct {
+	BodyContents []byte
+}
+
+func (e ErrPreconditionFailed) Error() string {
+	return fmt.Sprintf("status: 412, body: %s", e.BodyContents)
+}
+
+// ErrUnauthorized is returned when the API rejects the configured credentials (401).
+type ErrUnauthorized struct {
+	BodyContents []byte
+}
+
+func (e ErrUnauthorized) Error() string {
+	return fmt.Sprintf("status: 401, body: %s", e.BodyContents)
+}
+
+// ErrForbidden is returned when the configured credentials are valid but not
+// allowed to perform the request (403).
+type ErrForbidden struct {
+	BodyContents []byte
+}
+
+func (e ErrForbidden) Error() string {
+	return fmt.Sprintf("status: 403, body: %s", e.BodyContents)
+}
+
+// ErrValidation is returned when the API rejects the request payload (400 or
+// 422). Message holds the server's explanation.
+type ErrValidation struct {
+	StatusCode   int
+	Message      string
+	BodyContents []byte
+}
+
+func (e ErrValidation) Error() string {
+	return fmt.Sprintf("status: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// ErrRateLimited is returned when the API is throttling requests (429).
+// RetryAfter is zero if the server did not say when to retry.
+type ErrRateLimited struct {
+	RetryAfter   time.Duration
+	BodyContents []byte
+}
+
+func (e ErrRateLimited) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("status: 429, retry after: %s, body: %s", e.RetryAfter, e.BodyContents)
+	}
+	return fmt.Sprintf("status: 429, body: %s", e.BodyContents)
+}
+
+// ErrServerError is returned when the API fails to handle the request (5xx).
+type ErrServerError struct {
+	StatusCode   int
+	BodyContents []byte
+}
+
+func (e ErrServerError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.BodyContents)
+}
+
+// ErrUnexpectedStatus is returned for any other unsuccessful status code.
+type ErrUnexpectedStatus struct {
+	StatusCode   int
+	BodyContents []byte
+}
+
+func (e ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.BodyContents)
+}
+
+// ErrAmbiguousWrite is returned when a non-idempotent or ETag-guarded write
+// failed in a way that doesn't tell whether the API applied it, such as a
+// timeout or a 5xx response. Such writes are not retried automatically;
+// callers should read the resource back to find out what happened.
+type ErrAmbiguousWrite struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e ErrAmbiguousWrite) Error() string {
+	return fmt.Sprintf("%s %s may or may not have been applied: %v", e.Method, e.Path, e.Err)
+}
+
+func (e ErrAmbiguousWrite) Unwrap() error {
+	return e.Err
+}
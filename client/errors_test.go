@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respHeader http.Header
+		respBody   []byte
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			check: func(t *testing.T, err error) {
+				require.True(t, IsErrNotFound(err))
+			},
+		},
+		{
+			name:       "precondition failed",
+			statusCode: http.StatusPreconditionFailed,
+			check: func(t *testing.T, err error) {
+				require.True(t, IsErrPreconditionFailed(err))
+			},
+		},
+		{
+			name:       "unauthorized",
+			statusCode: http.StatusUnauthorized,
+			check: func(t *testing.T, err error) {
+				require.True(t, IsErrUnauthorized(err))
+				require.False(t, IsErrForbidden(err))
+			},
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			check: func(t *testing.T, err error) {
+				require.True(t, IsErrForbidden(err))
+				require.False(t, IsErrUnauthorized(err))
+			},
+		},
+		{
+			name:       "validation error with json message",
+			statusCode: http.StatusBadRequest,
+			respBody:   []byte(`{"message":"invalid aggregation type \"sum:countr\""}`),
+			check: func(t *testing.T, err error) {
+				var e ErrValidation
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, http.StatusBadRequest, e.StatusCode)
+				require.Equal(t, `invalid aggregation type "sum:countr"`, e.Message)
+			},
+		},
+		{
+			name:       "validation error with plain text body",
+			statusCode: http.StatusUnprocessableEntity,
+			respBody:   []byte("invalid match_type\n"),
+			check: func(t *testing.T, err error) {
+				var e ErrValidation
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, "invalid match_type", e.Message)
+			},
+		},
+		{
+			name:       "rate limited",
+			statusCode: http.StatusTooManyRequests,
+			respHeader: http.Header{"Retry-After": []string{"7"}},
+			check: func(t *testing.T, err error) {
+				var e ErrRateLimited
+				require.ErrorAs(t, err, &e)
+				require.True(t, IsErrRateLimited(err))
+				require.Equal(t, 7*time.Second, e.RetryAfter)
+			},
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusBadGateway,
+			check: func(t *testing.T, err error) {
+				var e ErrServerError
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, http.StatusBadGateway, e.StatusCode)
+			},
+		},
+		{
+			name:       "unexpected status",
+			statusCode: http.StatusConflict,
+			check: func(t *testing.T, err error) {
+				var e ErrUnexpectedStatus
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, http.StatusConflict, e.StatusCode)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMockServer(t)
+			defer s.close()
+
+			s.addExpected("GET", "/aggregations/recommendations/config",
+				withStatusCode(tt.statusCode),
+				withRespHeader(tt.respHeader),
+				withRespBody(tt.respBody),
+			)
+
+			c, err := New(s.server.URL, &Config{})
+			require.NoError(t, err)
+
+			_, err = c.AggregationRecommendationsConfig(context.Background())
+			require.Error(t, err)
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	require.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	require.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	require.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	require.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	require.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+}
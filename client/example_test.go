@@ -0,0 +1,34 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+// The URL is the bare tenant URL; the client adds the /aggregations and /v1
+// paths of each endpoint itself.
+func Example() {
+	ctx := context.Background()
+
+	c, err := client.New("https://<tenant-url>", &client.Config{
+		APIKey:    "<tenant-id>:<token>",
+		UserAgent: "my-tool/1.0",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// An empty segment ID reads the default segment.
+	rules, _, err := c.ReadAggregationRuleSet(ctx, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if rule, ok := model.AggregationRuleSet(rules).Match("http_requests_total"); ok {
+		fmt.Printf("aggregated by %s rule %q\n", rule.MatchType, rule.Metric)
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+const (
+	exemptionsEndpoint = "/v1/recommendations/exemptions"
+	exemptionEndpoint  = "/v1/recommendations/exemptions/%s"
+)
+
+func (c *Client) CreateExemption(ctx context.Context, segmentID string, ex model.Exemption) (model.Exemption, error) {
+	body, err := json.Marshal(ex)
+	if err != nil {
+		return model.Exemption{}, err
+	}
+
+	resp := exemptionResp{}
+
+	params := url.Values{
+		"segment": {segmentID},
+	}
+
+	err = c.request(ctx, "POST", exemptionsEndpoint, params, body, &resp)
+	if err != nil {
+		if IsErrAmbiguousWrite(err) {
+			return c.findCreatedExemption(ctx, segmentID, ex, err)
+		}
+		return model.Exemption{}, err
+	}
+
+	return resp.Result, nil
+}
+
+// findCreatedExemption finds out whether an exemption create that failed with
+// the ambiguous writeErr was applied, by listing the segment's exemptions. It
+// returns the created exemption if there is one for the metric with the
+// requested settings, and writeErr otherwise.
+func (c *Client) findCreatedExemption(ctx context.Context, segmentID string, ex model.Exemption, writeErr error) (model.Exemption, error) {
+	exemptions, err := c.ListExemptions(ctx, segmentID)
+	if err != nil {
+		return model.Exemption{}, errors.Join(writeErr, fmt.Errorf("could not list exemptions to check whether the create was applied: %w", err))
+	}
+
+	for _, got := range exemptions {
+		if got.Metric == ex.Metric &&
+			slices.Equal(got.KeepLabels, ex.KeepLabels) &&
+			got.DisableRecommendations == ex.DisableRecommendations &&
+			got.Reason == ex.Reason &&
+			got.ManagedBy == ex.ManagedBy {
+			return got, nil
+		}
+	}
+	return model.Exemption{}, fmt.Errorf("%w; listing the exemptions showed it was not applied", writeErr)
+}
+
+func (c *Client) ListExemptions(ctx context.Context, segmentID string) ([]model.Exemption, error) {
+	resp := exemptionsResp{}
+	params := url.Values{
+		"segment": {segmentID},
+	}
+
+	err := c.request(ctx, "GET", exemptionsEndpoint, params, nil, &resp)
+	return resp.Result, err
+}
+
+func (c *Client) ReadExemption(ctx context.Context, segmentID string, exID string) (model.Exemption, error) {
+	resp := exemptionResp{}
+	endpoint := fmt.Sprintf(exemptionEndpoint, exID)
+	params := url.Values{
+		"segment": {segmentID},
+	}
+
+	err := c.request(ctx, "GET", endpoint, params, nil, &resp)
+	return resp.Result, err
+}
+
+func (c *Client) UpdateExemption(ctx context.Context, segmentID string, ex model.Exemption) error {
+	body, err := json.Marshal(ex)
+	if err != nil {
+		return err
+	}
+	params := url.Values{
+		"segment": {segmentID},
+	}
+
+	endpoint := fmt.Sprintf(exemptionEndpoint, ex.ID)
+	return c.request(ctx, "PUT", endpoint, params, body, nil)
+}
+
+func (c *Client) DeleteExemption(ctx context.Context, segmentID string, exID string) error {
+	endpoint := fmt.Sprintf(exemptionEndpoint, exID)
+	params := url.Values{
+		"segment": {segmentID},
+	}
+
+	return c.request(ctx, "DELETE", endpoint, params, nil, nil)
+}
+
+type exemptionResp struct {
+	Result model.Exemption `json:"result"`
+}
+
+type exemptionsResp struct {
+	Result []model.Exemption `json:"result"`
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultDebugBodyMaxBytes is how much of request and response bodies is
+	// logged in debug mode, unless configured otherwise.
+	DefaultDebugBodyMaxBytes = 4096
+
+	redacted = "***"
+)
+
+// Logger receives the client's log entries about API requests. Entries never
+// contain the API key or the values of sensitive headers.
+type Logger interface {
+	// RequestContext returns the context the entries about a request are
+	// logged with. It is called once per request, before the request is
+	// sent, so that loggers carried by contexts can be set up for it.
+	RequestContext(ctx context.Context) context.Context
+	// Debug logs msg at the debug level with structured fields.
+	Debug(ctx context.Context, msg string, fields map[string]interface{})
+}
+
+// logContext returns ctx set up for logging a request.
+func (c *Client) logContext(ctx context.Context) context.Context {
+	if c.Cfg.Logger == nil {
+		return ctx
+	}
+	return c.Cfg.Logger.RequestContext(ctx)
+}
+
+// debug logs an entry through the configured logger, if any, masking the API
+// key wherever it would otherwise appear.
+func (c *Client) debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	if c.Cfg.Logger == nil {
+		return
+	}
+
+	masked := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		switch v := v.(type) {
+		case string:
+			masked[k] = c.mask(v)
+		case map[string]string:
+			m := make(map[string]string, len(v))
+			for hk, hv := range v {
+				m[hk] = c.mask(hv)
+			}
+			masked[k] = m
+		default:
+			masked[k] = v
+		}
+	}
+	c.Cfg.Logger.Debug(ctx, msg, masked)
+}
+
+func (c *Client) mask(s string) string {
+	if c.Cfg.APIKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.Cfg.APIKey, redacted)
+}
+
+// logRequest logs a request about to be sent and returns the fields
+// identifying it, to be logged along with its outcome. Headers and bodies
+// are only logged in debug mode.
+func (c *Client) logRequest(ctx context.Context, method, requestPath string, query url.Values, header http.Header, body []byte) map[string]interface{} {
+	fields := map[string]interface{}{
+		"method":  method,
+		"path":    requestPath,
+		"segment": query.Get("segment"),
+	}
+
+	reqFields := make(map[string]interface{}, len(fields)+2)
+	for k, v := range fields {
+		reqFields[k] = v
+	}
+	if c.Cfg.Debug {
+		reqFields["request_headers"] = c.redactHeaders(header)
+		reqFields["request_body"] = c.truncateBody(body)
+	}
+	c.debug(ctx, "Sending API request", reqFields)
+
+	return fields
+}
+
+// logResponse logs the outcome of the request identified by fields.
+func (c *Client) logResponse(ctx context.Context, fields map[string]interface{}, statusCode int, body []byte, duration time.Duration) {
+	fields["status"] = statusCode
+	fields["duration_ms"] = duration.Milliseconds()
+	if c.Cfg.Debug {
+		fields["response_body"] = c.truncateBody(body)
+	}
+	c.debug(ctx, "Received API response", fields)
+}
+
+// logRequestError logs a request identified by fields that got no response.
+func (c *Client) logRequestError(ctx context.Context, fields map[string]interface{}, err error, duration time.Duration) {
+	fields["error"] = err.Error()
+	fields["duration_ms"] = duration.Milliseconds()
+	c.debug(ctx, "API request failed", fields)
+}
+
+// redactHeaders renders headers for logging, hiding the values of the
+// Authorization header and of every header configured through HTTPHeaders,
+// which commonly carry credentials.
+func (c *Client) redactHeaders(header http.Header) map[string]string {
+	sensitive := map[string]bool{"Authorization": true}
+	for k := range c.Cfg.HTTPHeaders {
+		sensitive[http.CanonicalHeaderKey(k)] = true
+	}
+
+	out := make(map[string]string, len(header))
+	for k, vals := range header {
+		if sensitive[http.CanonicalHeaderKey(k)] {
+			out[k] = redacted
+			continue
+		}
+		out[k] = strings.Join(vals, ", ")
+	}
+	return out
+}
+
+// truncateBody renders a body for logging, cut down to the configured size.
+func (c *Client) truncateBody(body []byte) string {
+	maxBytes := c.Cfg.DebugBodyMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultDebugBodyMaxBytes
+	}
+
+	if len(body) <= maxBytes {
+		return string(body)
+	}
+
+	// Cut at a rune boundary, so that the logged body stays valid UTF-8.
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", body[:cut], len(body)-cut)
+}
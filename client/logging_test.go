@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	c, err := New("http://localhost", &Config{
+		APIKey:      "123:secret",
+		HTTPHeaders: map[string]string{"x-scope-orgid": "123", "X-Custom-Token": "token"},
+	})
+	require.NoError(t, err)
+
+	req, err := c.newRequest(context.Background(), "GET", "/aggregations/rules", nil, http.Header{"If-Match": []string{`"1"`}}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		"Authorization":  redacted,
+		"X-Scope-Orgid":  redacted,
+		"X-Custom-Token": redacted,
+		"If-Match":       `"1"`,
+		"User-Agent":     defaultUserAgent,
+		"Content-Type":   "application/json",
+	}, c.redactHeaders(req.Header))
+}
+
+func TestTruncateBody(t *testing.T) {
+	c, err := New("http://localhost", &Config{DebugBodyMaxBytes: 8})
+	require.NoError(t, err)
+
+	require.Equal(t, "", c.truncateBody(nil))
+	require.Equal(t, "12345678", c.truncateBody([]byte("12345678")))
+	require.Equal(t, "12345678... (2 more bytes)", c.truncateBody([]byte("1234567890")))
+
+	// Multi-byte runes aren't split.
+	require.Equal(t, "1234567... (4 more bytes)", c.truncateBody([]byte("1234567é89")))
+	require.Equal(t, "123456é... (2 more bytes)", c.truncateBody([]byte("123456é89")))
+
+	c.Cfg.DebugBodyMaxBytes = 0
+	long := strings.Repeat("a", DefaultDebugBodyMaxBytes+1)
+	require.Equal(t, long[:DefaultDebugBodyMaxBytes]+"... (1 more bytes)", c.truncateBody([]byte(long)))
+}
+
+type logEntry struct {
+	msg    string
+	fields map[string]interface{}
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) RequestContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (l *testLogger) Debug(_ context.Context, msg string, fields map[string]interface{}) {
+	l.entries = append(l.entries, logEntry{msg: msg, fields: fields})
+}
+
+func TestLogger(t *testing.T) {
+	s := newMockServer(t)
+	defer s.close()
+
+	logger := &testLogger{}
+	c, err := New(s.server.URL, &Config{APIKey: "123:secret", Logger: logger, Debug: true})
+	require.NoError(t, err)
+
+	s.addExpected("GET", "/aggregations/rules", withParams(url.Values{"segment": {"a"}}), withRespHeader(http.Header{"Etag": []string{`"1"`}}), withRespBody([]byte(`[{"metric":"123:secret"}]`)))
+	_, _, err = c.ReadAggregationRuleSet(context.Background(), "a")
+	require.NoError(t, err)
+
+	require.Len(t, logger.entries, 2)
+	require.Equal(t, "Sending API request", logger.entries[0].msg)
+	require.Equal(t, "GET", logger.entries[0].fields["method"])
+	require.Equal(t, "/aggregations/rules", logger.entries[0].fields["path"])
+	require.Equal(t, "a", logger.entries[0].fields["segment"])
+	require.Equal(t, redacted, logger.entries[0].fields["request_headers"].(map[string]string)["Authorization"])
+	require.Equal(t, "Received API response", logger.entries[1].msg)
+	require.Equal(t, http.StatusOK, logger.entries[1].fields["status"])
+	require.Equal(t, `[{"metric":"***"}]`, logger.entries[1].fields["response_body"])
+}
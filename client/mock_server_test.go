@@ -117,3 +117,9 @@ func withParams(p url.Values) mockRequestOption {
 		r.params = p
 	}
 }
+
+func withStatusCode(code int) mockRequestOption {
+	return func(r *mockServerResponse) {
+		r.statusCode = code
+	}
+}
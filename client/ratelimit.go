@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// rateLimiter spaces out requests so that no more than a fixed number are
+// started per second.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the caller may send a request or ctx is done. A nil
+// rateLimiter never blocks.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimitTransport wraps base so that no more than requestsPerSecond
+// requests are sent per second. The limit applies to every attempt, so when
+// requests are retried it must wrap the transport that the retrying client
+// sends through rather than the retrying client itself. A non-positive
+// requestsPerSecond returns base unchanged.
+func RateLimitTransport(base http.RoundTripper, requestsPerSecond float64) http.RoundTripper {
+	limiter := newRateLimiter(requestsPerSecond)
+	if limiter == nil {
+		return base
+	}
+	return &rateLimitedTransport{base: base, limiter: limiter}
+}
+
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context()); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// RetryBackoff is a retryablehttp.Backoff that waits for as long as the API
+// asks through the Retry-After header of 429 and 503 responses, bounded by
+// maxWait, and backs off exponentially otherwise.
+func RetryBackoff(minWait, maxWait time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); wait > 0 {
+			return clampDuration(wait, minWait, maxWait)
+		}
+	}
+
+	return retryablehttp.DefaultBackoff(minWait, maxWait, attemptNum, nil)
+}
+
+func clampDuration(d, lower, upper time.Duration) time.Duration {
+	if d < lower {
+		return lower
+	}
+	if d > upper {
+		return upper
+	}
+	return d
+}
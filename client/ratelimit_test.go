@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l := newRateLimiter(0)
+		require.Nil(t, l)
+		require.NoError(t, l.wait(context.Background()))
+	})
+
+	t.Run("spaces requests out", func(t *testing.T) {
+		l := newRateLimiter(20)
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			require.NoError(t, l.wait(context.Background()))
+		}
+		// The first request goes out immediately, the other four wait 50ms each.
+		require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("honors context cancellation", func(t *testing.T) {
+		l := newRateLimiter(0.1)
+		require.NoError(t, l.wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, l.wait(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestRateLimitTransportLimitsRetries(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		starts []time.Time
+	)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		calls := len(starts)
+		mu.Unlock()
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer s.Close()
+
+	retryClient := retryablehttp.NewClient()
+	retryClient.HTTPClient = &http.Client{Transport: RateLimitTransport(http.DefaultTransport, 10)}
+	retryClient.Logger = nil
+	retryClient.RetryWaitMin = time.Millisecond
+	retryClient.RetryWaitMax = time.Millisecond
+	retryClient.Backoff = RetryBackoff
+
+	c, err := New(s.URL, &Config{HttpClient: retryClient.StandardClient()})
+	require.NoError(t, err)
+
+	_, err = c.ListSegments(context.Background())
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, starts, 3)
+	// The retries after the 429s wait for the limiter rather than only for
+	// the 1ms backoff. Allow some slack for when the server sees them.
+	for i := 1; i < len(starts); i++ {
+		require.GreaterOrEqual(t, starts[i].Sub(starts[i-1]), 90*time.Millisecond, "attempt %d", i)
+	}
+}
+
+func TestRateLimitTransportDisabled(t *testing.T) {
+	require.Equal(t, http.DefaultTransport, RateLimitTransport(http.DefaultTransport, 0))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	resp := func(statusCode int, retryAfter string) *http.Response {
+		r := &http.Response{StatusCode: statusCode, Header: http.Header{}}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		return r
+	}
+
+	minWait, maxWait := time.Second, 30*time.Second
+
+	require.Equal(t, 5*time.Second, RetryBackoff(minWait, maxWait, 0, resp(http.StatusTooManyRequests, "5")))
+	require.Equal(t, 5*time.Second, RetryBackoff(minWait, maxWait, 0, resp(http.StatusServiceUnavailable, "5")))
+	require.Equal(t, maxWait, RetryBackoff(minWait, maxWait, 0, resp(http.StatusTooManyRequests, "3600")))
+	require.Equal(t, minWait, RetryBackoff(minWait, maxWait, 0, resp(http.StatusTooManyRequests, "0")))
+	require.Equal(t, 4*time.Second, RetryBackoff(minWait, maxWait, 2, resp(http.StatusTooManyRequests, "")))
+	require.Equal(t, 4*time.Second, RetryBackoff(minWait, maxWait, 2, resp(http.StatusInternalServerError, "5")))
+	require.Equal(t, 2*time.Second, RetryBackoff(minWait, maxWait, 1, nil))
+}
+
+func TestRetriesHonorRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer s.Close()
+
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.RetryWaitMin = 10 * time.Millisecond
+	retryClient.RetryWaitMax = 5 * time.Second
+	retryClient.Backoff = RetryBackoff
+
+	c, err := New(s.URL, &Config{HttpClient: retryClient.StandardClient()})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.ListSegments(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRetriesExhaustedReturnRateLimitedError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer s.Close()
+
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.RetryMax = 1
+	retryClient.RetryWaitMin = time.Millisecond
+	retryClient.RetryWaitMax = time.Millisecond
+	retryClient.Backoff = RetryBackoff
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	c, err := New(s.URL, &Config{HttpClient: retryClient.StandardClient()})
+	require.NoError(t, err)
+
+	_, err = c.ListSegments(context.Background())
+	require.True(t, IsErrRateLimited(err), "unexpected error: %v", err)
+}
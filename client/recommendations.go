@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+const (
+	recommendationsEndpoint       = "/aggregations/recommendations"
+	recommendationsConfigEndpoint = "/aggregations/recommendations/config"
+)
+
+func (c *Client) AggregationRecommendations(ctx context.Context, segmentID string, verbose bool, action []string) ([]model.AggregationRecommendation, error) {
+	var recs []model.AggregationRecommendation
+	params := url.Values{}
+	if segmentID != "" {
+		params.Add("segment", segmentID)
+	}
+	if verbose {
+		params.Add("verbose", "true")
+	}
+	for _, a := range action {
+		params.Add("action", a)
+	}
+	err := c.request(ctx, "GET", recommendationsEndpoint, params, nil, &recs)
+	return recs, err
+}
+
+func (c *Client) AggregationRecommendationsConfig(ctx context.Context) (model.AggregationRecommendationConfiguration, error) {
+	config := model.AggregationRecommendationConfiguration{}
+	err := c.request(ctx, "GET", recommendationsConfigEndpoint, nil, nil, &config)
+	return config, err
+}
+
+func (c *Client) UpdateAggregationRecommendationsConfig(ctx context.Context, config model.AggregationRecommendationConfiguration) error {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// The POST replaces the whole configuration, so it is safe to resend.
+	return c.replaceRequest(ctx, "POST", recommendationsConfigEndpoint, nil, body, nil)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// requestClass tells how safe it is to send a request again after a failure
+// that leaves it unknown whether the API applied it.
+type requestClass int
+
+const (
+	// classIdempotent requests can be resent freely: applying them twice has
+	// the same effect as applying them once.
+	classIdempotent requestClass = iota
+	// classNonIdempotent requests may have a different effect when applied
+	// twice, e.g. creating two exemptions instead of one.
+	classNonIdempotent
+	// classGuarded requests carry an If-Match ETag. Once applied, the ETag
+	// changes and resending them fails with a 412 even though the change
+	// landed.
+	classGuarded
+)
+
+type requestClassKey struct{}
+
+func classifyRequest(method string, header http.Header) requestClass {
+	if header.Get("If-Match") != "" {
+		return classGuarded
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return classIdempotent
+	default:
+		return classNonIdempotent
+	}
+}
+
+func withRequestClass(ctx context.Context, class requestClass) context.Context {
+	return context.WithValue(ctx, requestClassKey{}, class)
+}
+
+func requestClassFromContext(ctx context.Context) requestClass {
+	class, _ := ctx.Value(requestClassKey{}).(requestClass)
+	return class
+}
+
+// CheckRetry is a retryablehttp.CheckRetry policy that only resends
+// non-idempotent and ETag-guarded requests when the failure proves that the
+// API did not apply them. Other requests follow the default policy.
+func CheckRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil || requestClassFromContext(ctx) == classIdempotent {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	if err != nil {
+		if isConnectError(err) {
+			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+		}
+		return false, nil
+	}
+
+	// A throttled request was rejected before being handled.
+	return resp.StatusCode == http.StatusTooManyRequests, nil
+}
+
+// isConnectError reports whether err happened while establishing the
+// connection, before any part of the request was sent.
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
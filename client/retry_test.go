@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+func TestClassifyRequest(t *testing.T) {
+	guarded := http.Header{"If-Match": []string{`"1"`}}
+
+	require.Equal(t, classIdempotent, classifyRequest(http.MethodGet, nil))
+	require.Equal(t, classIdempotent, classifyRequest(http.MethodPut, nil))
+	require.Equal(t, classIdempotent, classifyRequest(http.MethodDelete, nil))
+	require.Equal(t, classNonIdempotent, classifyRequest(http.MethodPost, nil))
+	require.Equal(t, classGuarded, classifyRequest(http.MethodPost, guarded))
+	require.Equal(t, classGuarded, classifyRequest(http.MethodPut, guarded))
+	require.Equal(t, classGuarded, classifyRequest(http.MethodDelete, guarded))
+}
+
+func TestCheckRetry(t *testing.T) {
+	timeout := errors.New("read: connection reset by peer")
+	refused := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name  string
+		class requestClass
+		resp  *http.Response
+		err   error
+		retry bool
+	}{
+		{name: "idempotent server error", class: classIdempotent, resp: &http.Response{StatusCode: 502}, retry: true},
+		{name: "idempotent transport error", class: classIdempotent, err: timeout, retry: true},
+		{name: "guarded server error", class: classGuarded, resp: &http.Response{StatusCode: 502}, retry: false},
+		{name: "guarded transport error", class: classGuarded, err: timeout, retry: false},
+		{name: "guarded connection refused", class: classGuarded, err: refused, retry: true},
+		{name: "guarded rate limited", class: classGuarded, resp: &http.Response{StatusCode: 429}, retry: true},
+		{name: "non-idempotent server error", class: classNonIdempotent, resp: &http.Response{StatusCode: 500}, retry: false},
+		{name: "non-idempotent success", class: classNonIdempotent, resp: &http.Response{StatusCode: 200}, retry: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, _ := CheckRetry(withRequestClass(context.Background(), tt.class), tt.resp, tt.err)
+			require.Equal(t, tt.retry, retry)
+		})
+	}
+}
+
+func TestAmbiguousWriteErrors(t *testing.T) {
+	s := newMockServer(t)
+	defer s.close()
+
+	c, err := New(s.server.URL, &Config{})
+	require.NoError(t, err)
+
+	s.addExpected("POST", "/aggregations/rule/a", withReqBody([]byte(`{"metric":"a"}`)), withStatusCode(http.StatusBadGateway))
+	_, err = c.CreateAggregationRule(context.Background(), "", model.AggregationRule{Metric: "a"}, `"1"`)
+	require.True(t, IsErrAmbiguousWrite(err))
+	require.True(t, IsErrServerError(err))
+
+	// Idempotent requests can be retried, so their failures aren't ambiguous.
+	s.addExpected("GET", "/aggregations/rule/a", withStatusCode(http.StatusBadGateway))
+	_, _, err = c.ReadAggregationRule(context.Background(), "", "a")
+	require.False(t, IsErrAmbiguousWrite(err))
+	require.True(t, IsErrServerError(err))
+
+	// Neither are failures that show the request was rejected.
+	s.addExpected("POST", "/aggregations/rule/a", withReqBody([]byte(`{"metric":"a"}`)), withStatusCode(http.StatusPreconditionFailed))
+	_, err = c.CreateAggregationRule(context.Background(), "", model.AggregationRule{Metric: "a"}, `"1"`)
+	require.False(t, IsErrAmbiguousWrite(err))
+}
+
+func TestRecommendationsConfigUpdatesAreRetried(t *testing.T) {
+	s := newMockServer(t)
+	defer s.close()
+
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.RetryWaitMin = time.Millisecond
+	retryClient.RetryWaitMax = time.Millisecond
+	retryClient.CheckRetry = CheckRetry
+
+	c, err := New(s.server.URL, &Config{HttpClient: retryClient.StandardClient()})
+	require.NoError(t, err)
+
+	// Replacing the whole configuration is safe to resend.
+	config := model.AggregationRecommendationConfiguration{KeepLabels: []string{"a"}}
+	body, err := json.Marshal(config)
+	require.NoError(t, err)
+	s.addExpected("POST", "/aggregations/recommendations/config", withReqBody(body), withStatusCode(http.StatusBadGateway))
+	s.addExpected("POST", "/aggregations/recommendations/config", withReqBody(body), withStatusCode(http.StatusOK))
+	require.NoError(t, c.UpdateAggregationRecommendationsConfig(context.Background(), config))
+}
+
+func TestAmbiguousCreatesAreCheckedByListing(t *testing.T) {
+	s := newMockServer(t)
+	defer s.close()
+
+	c, err := New(s.server.URL, &Config{})
+	require.NoError(t, err)
+
+	segmentParams := withParams(url.Values{"segment": {""}})
+
+	t.Run("exemption applied", func(t *testing.T) {
+		ex := model.Exemption{Metric: "a", KeepLabels: []string{"pod"}}
+		s.addExpected("POST", "/v1/recommendations/exemptions", segmentParams, withReqBody([]byte(`{"id":"","metric":"a","keep_labels":["pod"],"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`)), withStatusCode(http.StatusBadGateway))
+		s.addExpected("GET", "/v1/recommendations/exemptions", segmentParams, withRespBody([]byte(`{"result":[{"id":"other","metric":"b"},{"id":"1","metric":"a","keep_labels":["pod"]}]}`)))
+
+		got, err := c.CreateExemption(context.Background(), "", ex)
+		require.NoError(t, err)
+		require.Equal(t, "1", got.ID)
+	})
+
+	t.Run("exemption not applied", func(t *testing.T) {
+		ex := model.Exemption{Metric: "a", KeepLabels: []string{"pod"}}
+		s.addExpected("POST", "/v1/recommendations/exemptions", segmentParams, withReqBody([]byte(`{"id":"","metric":"a","keep_labels":["pod"],"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`)), withStatusCode(http.StatusBadGateway))
+		s.addExpected("GET", "/v1/recommendations/exemptions", segmentParams, withRespBody([]byte(`{"result":[{"id":"1","metric":"a","keep_labels":["instance"]}]}`)))
+
+		_, err := c.CreateExemption(context.Background(), "", ex)
+		require.True(t, IsErrAmbiguousWrite(err))
+		require.ErrorContains(t, err, "not applied")
+	})
+
+	t.Run("segment applied", func(t *testing.T) {
+		segment := model.Segment{Name: "a", Selector: `{a="b"}`}
+		s.addExpected("POST", "/aggregations/rules/segments", withReqBody([]byte(`{"id":"","name":"a","selector":"{a=\"b\"}","fallback_to_default":false}`)), withStatusCode(http.StatusBadGateway))
+		s.addExpected("GET", "/aggregations/rules/segments", withRespBody([]byte(`[{"id":"1","name":"a","selector":"{a=\"b\"}"}]`)))
+
+		got, err := c.CreateSegment(context.Background(), segment)
+		require.NoError(t, err)
+		require.Equal(t, "1", got.ID)
+	})
+
+	t.Run("segment not applied", func(t *testing.T) {
+		segment := model.Segment{Name: "a", Selector: `{a="b"}`}
+		s.addExpected("POST", "/aggregations/rules/segments", withReqBody([]byte(`{"id":"","name":"a","selector":"{a=\"b\"}","fallback_to_default":false}`)), withStatusCode(http.StatusBadGateway))
+		s.addExpected("GET", "/aggregations/rules/segments", withRespBody([]byte(`[]`)))
+
+		_, err := c.CreateSegment(context.Background(), segment)
+		require.True(t, IsErrAmbiguousWrite(err))
+		require.ErrorContains(t, err, "not applied")
+	})
+}
@@ -1,12 +1,13 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 const (
@@ -15,9 +16,9 @@ const (
 	aggregationRuleEndpoint  = "/aggregations/rule/%s"
 )
 
-func (c *Client) SegmentedAggregationRules() ([]model.SegmentedRuleSet, error) {
+func (c *Client) SegmentedAggregationRules(ctx context.Context) ([]model.SegmentedRuleSet, error) {
 	var rules []model.SegmentedRuleSet
-	err := c.request("GET", segmentedRulesEndpoint, nil, nil, &rules)
+	err := c.request(ctx, "GET", segmentedRulesEndpoint, nil, nil, &rules)
 	if err != nil {
 		return rules, err
 	}
@@ -25,7 +26,7 @@ func (c *Client) SegmentedAggregationRules() ([]model.SegmentedRuleSet, error) {
 	return rules, err
 }
 
-func (c *Client) CreateAggregationRule(segmentID string, rule model.AggregationRule, etag string) (string, error) {
+func (c *Client) CreateAggregationRule(ctx context.Context, segmentID string, rule model.AggregationRule, etag string) (string, error) {
 	body, err := json.Marshal(rule)
 	if err != nil {
 		return "", err
@@ -43,7 +44,7 @@ func (c *Client) CreateAggregationRule(segmentID string, rule model.AggregationR
 
 	endpoint := fmt.Sprintf(aggregationRuleEndpoint, rule.Metric)
 
-	respHeader, err := c.requestWithHeaders("POST", endpoint, params, reqHeader, body, nil)
+	respHeader, err := c.requestWithHeaders(ctx, "POST", endpoint, params, reqHeader, body, nil)
 	if err != nil {
 		return "", err
 	}
@@ -56,7 +57,7 @@ func (c *Client) CreateAggregationRule(segmentID string, rule model.AggregationR
 	return newEtag, nil
 }
 
-func (c *Client) ReadAggregationRule(segmentID string, metric string) (model.AggregationRule, string, error) {
+func (c *Client) ReadAggregationRule(ctx context.Context, segmentID string, metric string) (model.AggregationRule, string, error) {
 	rule := model.AggregationRule{}
 	endpoint := fmt.Sprintf(aggregationRuleEndpoint, metric)
 
@@ -67,7 +68,7 @@ func (c *Client) ReadAggregationRule(segmentID string, metric string) (model.Agg
 		}
 	}
 
-	respHeader, err := c.requestWithHeaders("GET", endpoint, params, nil, nil, &rule)
+	respHeader, err := c.requestWithHeaders(ctx, "GET", endpoint, params, nil, nil, &rule)
 	if err != nil {
 		return rule, "", err
 	}
@@ -80,7 +81,7 @@ func (c *Client) ReadAggregationRule(segmentID string, metric string) (model.Agg
 	return rule, newEtag, nil
 }
 
-func (c *Client) UpdateAggregationRule(segmentID string, rule model.AggregationRule, etag string) (string, error) {
+func (c *Client) UpdateAggregationRule(ctx context.Context, segmentID string, rule model.AggregationRule, etag string) (string, error) {
 	body, err := json.Marshal(rule)
 	if err != nil {
 		return "", err
@@ -98,7 +99,7 @@ func (c *Client) UpdateAggregationRule(segmentID string, rule model.AggregationR
 
 	endpoint := fmt.Sprintf(aggregationRuleEndpoint, rule.Metric)
 
-	respHeader, err := c.requestWithHeaders("PUT", endpoint, params, reqHeader, body, nil)
+	respHeader, err := c.requestWithHeaders(ctx, "PUT", endpoint, params, reqHeader, body, nil)
 	if err != nil {
 		return "", err
 	}
@@ -111,7 +112,7 @@ func (c *Client) UpdateAggregationRule(segmentID string, rule model.AggregationR
 	return newEtag, nil
 }
 
-func (c *Client) DeleteAggregationRule(segmentID string, metric, etag string) (string, error) {
+func (c *Client) DeleteAggregationRule(ctx context.Context, segmentID string, metric, etag string) (string, error) {
 	reqHeader := make(http.Header)
 	reqHeader.Add("If-Match", etag)
 
@@ -124,7 +125,7 @@ func (c *Client) DeleteAggregationRule(segmentID string, metric, etag string) (s
 		}
 	}
 
-	respHeader, err := c.requestWithHeaders("DELETE", endpoint, params, reqHeader, nil, nil)
+	respHeader, err := c.requestWithHeaders(ctx, "DELETE", endpoint, params, reqHeader, nil, nil)
 	if err != nil {
 		return "", err
 	}
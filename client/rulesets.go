@@ -1,15 +1,16 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
-func (c *Client) ReadAggregationRuleSet(segmentID string) ([]model.AggregationRule, string, error) {
+func (c *Client) ReadAggregationRuleSet(ctx context.Context, segmentID string) ([]model.AggregationRule, string, error) {
 	rules := []model.AggregationRule{}
 
 	var params url.Values
@@ -19,7 +20,7 @@ func (c *Client) ReadAggregationRuleSet(segmentID string) ([]model.AggregationRu
 		}
 	}
 
-	respHeader, err := c.requestWithHeaders("GET", aggregationRulesEndpoint, params, nil, nil, &rules)
+	respHeader, err := c.requestWithHeaders(ctx, "GET", aggregationRulesEndpoint, params, nil, nil, &rules)
 	if err != nil {
 		return rules, "", err
 	}
@@ -32,7 +33,7 @@ func (c *Client) ReadAggregationRuleSet(segmentID string) ([]model.AggregationRu
 	return rules, newEtag, nil
 }
 
-func (c *Client) UpdateAggregationRuleSet(segmentID string, rules []model.AggregationRule, etag string) (string, error) {
+func (c *Client) UpdateAggregationRuleSet(ctx context.Context, segmentID string, rules []model.AggregationRule, etag string) (string, error) {
 	// We don't want to send null to the server
 	if rules == nil {
 		rules = []model.AggregationRule{}
@@ -53,7 +54,7 @@ func (c *Client) UpdateAggregationRuleSet(segmentID string, rules []model.Aggreg
 		}
 	}
 
-	respHeader, err := c.requestWithHeaders("POST", aggregationRulesEndpoint, params, reqHeader, body, nil)
+	respHeader, err := c.requestWithHeaders(ctx, "POST", aggregationRulesEndpoint, params, reqHeader, body, nil)
 	if err != nil {
 		return "", err
 	}
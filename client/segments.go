@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+const (
+	segmentsEndpoint = "/aggregations/rules/segments"
+)
+
+func (c *Client) CreateSegment(ctx context.Context, s model.Segment) (model.Segment, error) {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return model.Segment{}, err
+	}
+
+	c.segmentMutex.Lock()
+	defer c.segmentMutex.Unlock()
+
+	var resp model.Segment
+	err = c.request(ctx, "POST", segmentsEndpoint, nil, body, &resp)
+	if err != nil {
+		if IsErrAmbiguousWrite(err) {
+			return c.findCreatedSegment(ctx, s, err)
+		}
+		return model.Segment{}, err
+	}
+
+	return resp, nil
+}
+
+// findCreatedSegment finds out whether a segment create that failed with the
+// ambiguous writeErr was applied, by listing the segments. Segment names are
+// unique, so it returns the segment with the requested name if it has the
+// requested settings, and writeErr otherwise.
+//
+// Must be called with segmentMutex held.
+func (c *Client) findCreatedSegment(ctx context.Context, s model.Segment, writeErr error) (model.Segment, error) {
+	segments := []model.Segment{}
+	if err := c.request(ctx, "GET", segmentsEndpoint, nil, nil, &segments); err != nil {
+		return model.Segment{}, errors.Join(writeErr, fmt.Errorf("could not list segments to check whether the create was applied: %w", err))
+	}
+
+	for _, got := range segments {
+		if got.Name == s.Name && got.Selector == s.Selector && got.FallbackToDefault == s.FallbackToDefault {
+			return got, nil
+		}
+	}
+	return model.Segment{}, fmt.Errorf("%w; listing the segments showed it was not applied", writeErr)
+}
+
+func (c *Client) ListSegments(ctx context.Context) ([]model.Segment, error) {
+	c.segmentMutex.Lock()
+	defer c.segmentMutex.Unlock()
+
+	resp := []model.Segment{}
+	err := c.request(ctx, "GET", segmentsEndpoint, nil, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) ReadSegment(ctx context.Context, id string) (model.Segment, error) {
+	c.segmentMutex.Lock()
+	defer c.segmentMutex.Unlock()
+
+	resp := []model.Segment{}
+	err := c.request(ctx, "GET", segmentsEndpoint, nil, nil, &resp)
+	if err != nil {
+		return model.Segment{}, err
+	}
+
+	for _, segment := range resp {
+		if segment.ID == id {
+			return segment, nil
+		}
+	}
+
+	return model.Segment{}, ErrNotFound{
+		BodyContents: []byte("segment not found"),
+	}
+}
+
+func (c *Client) UpdateSegment(ctx context.Context, s model.Segment) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	c.segmentMutex.Lock()
+	defer c.segmentMutex.Unlock()
+
+	params := url.Values{
+		"segment": []string{s.ID},
+	}
+	return c.request(ctx, "PUT", segmentsEndpoint, params, body, nil)
+}
+
+func (c *Client) DeleteSegment(ctx context.Context, id string) error {
+	c.segmentMutex.Lock()
+	defer c.segmentMutex.Unlock()
+
+	params := url.Values{
+		"segment": []string{id},
+	}
+	return c.request(ctx, "DELETE", segmentsEndpoint, params, nil, nil)
+}
@@ -0,0 +1,10 @@
+data "grafana-adaptive-metrics_exemptions" "all" {
+}
+
+data "grafana-adaptive-metrics_exemptions" "unmanaged" {
+  managed_by = ""
+}
+
+output "exempted_metrics" {
+  value = data.grafana-adaptive-metrics_exemptions.all.exemptions[*].metric
+}
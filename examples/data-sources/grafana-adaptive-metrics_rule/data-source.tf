@@ -0,0 +1,7 @@
+data "grafana-adaptive-metrics_rule" "cpu" {
+  metric = "cpu_usage_seconds_total"
+}
+
+output "cpu_rule_owner" {
+  value = data.grafana-adaptive-metrics_rule.cpu.managed_by
+}
@@ -0,0 +1,11 @@
+data "grafana-adaptive-metrics_segment" "mimir" {
+  name = "mimir team"
+}
+
+data "grafana-adaptive-metrics_ruleset" "mimir" {
+  segment = data.grafana-adaptive-metrics_segment.mimir.id
+}
+
+output "unmanaged_rules" {
+  value = [for r in data.grafana-adaptive-metrics_ruleset.mimir.rules : r.metric if r.managed_by != "terraform"]
+}
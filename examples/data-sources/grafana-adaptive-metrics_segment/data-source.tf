@@ -0,0 +1,14 @@
+data "grafana-adaptive-metrics_segment" "mimir" {
+  name = "mimir team"
+}
+
+resource "grafana-adaptive-metrics_ruleset" "mimir" {
+  segment = data.grafana-adaptive-metrics_segment.mimir.id
+  rules   = [
+    {
+      metric       = "cortex_request_duration_seconds_bucket"
+      drop_labels  = ["pod"]
+      aggregations = ["sum:counter"]
+    },
+  ]
+}
@@ -0,0 +1,10 @@
+data "grafana-adaptive-metrics_segments" "all" {
+}
+
+data "grafana-adaptive-metrics_segments" "mimir" {
+  selector = "{namespace=\"mimir\"}"
+}
+
+output "segment_ids" {
+  value = data.grafana-adaptive-metrics_segments.all.segments[*].id
+}
@@ -0,0 +1,5 @@
+# Import an exemption from the default segment
+terraform import grafana-adaptive-metrics_exemption.ex1 $EXEMPTION_ID
+
+# Import an exemption from a custom segment
+terraform import grafana-adaptive-metrics_exemption.ex1 $CUSTOM_SEGMENT_ID/$EXEMPTION_ID
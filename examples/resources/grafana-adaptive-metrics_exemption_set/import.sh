@@ -0,0 +1,5 @@
+# Import the exemption set of the default segment
+terraform import grafana-adaptive-metrics_exemption_set.default default
+
+# Import the exemption set of a custom segment
+terraform import grafana-adaptive-metrics_exemption_set.custom $CUSTOM_SEGMENT_ID
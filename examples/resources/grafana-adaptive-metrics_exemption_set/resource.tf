@@ -0,0 +1,29 @@
+# Own every exemption of the default segment
+resource "grafana-adaptive-metrics_exemption_set" "default" {
+  exemptions = [
+    {
+      metric      = "prometheus_request_duration_seconds_sum"
+      keep_labels = ["namespace", "cluster"]
+    },
+    {
+      metric                  = "up"
+      disable_recommendations = true
+      reason                  = "Used by availability alerts"
+    },
+  ]
+}
+
+# Manage exemptions of a custom segment next to the ones created in the UI
+data "grafana-adaptive-metrics_segment" "custom" {
+  name = "custom"
+}
+
+resource "grafana-adaptive-metrics_exemption_set" "custom" {
+  segment          = data.grafana-adaptive-metrics_segment.custom.id
+  ignore_unmanaged = true
+  exemptions = [
+    {
+      metric = "kube_pod_info"
+    },
+  ]
+}
@@ -0,0 +1,5 @@
+# Import a rule from the default segment
+terraform import grafana-adaptive-metrics_rule.agent_request_duration_seconds_sum $METRIC
+
+# Import a rule from a custom segment
+terraform import grafana-adaptive-metrics_rule.agent_request_duration_seconds_sum $CUSTOM_SEGMENT_ID/$METRIC
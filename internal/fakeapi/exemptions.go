@@ -0,0 +1,106 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type exemptionResult struct {
+	Result model.Exemption `json:"result"`
+}
+
+type exemptionsResult struct {
+	Result []model.Exemption `json:"result"`
+}
+
+func (s *Server) handleExemptions(w http.ResponseWriter, r *http.Request) {
+	segmentID, ok := s.segmentID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		exemptions := s.exemptions[segmentID]
+		if exemptions == nil {
+			exemptions = []model.Exemption{}
+		}
+		writeJSON(w, exemptionsResult{Result: exemptions})
+
+	case http.MethodPost:
+		var ex model.Exemption
+		if !readJSON(w, r, &ex) || !s.validExemption(w, segmentID, ex, "") {
+			return
+		}
+
+		now := s.now().UTC().Truncate(time.Millisecond)
+		ex.ID = s.newID()
+		ex.CreatedAt = now
+		ex.UpdatedAt = now
+		s.exemptions[segmentID] = append(s.exemptions[segmentID], ex)
+		writeJSON(w, exemptionResult{Result: ex})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+func (s *Server) handleExemption(w http.ResponseWriter, r *http.Request, id string) {
+	segmentID, ok := s.segmentID(w, r)
+	if !ok {
+		return
+	}
+
+	exemptions := s.exemptions[segmentID]
+	idx := slices.IndexFunc(exemptions, func(ex model.Exemption) bool {
+		return ex.ID == id
+	})
+	if idx < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("exemption %q not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, exemptionResult{Result: exemptions[idx]})
+
+	case http.MethodPut:
+		var ex model.Exemption
+		if !readJSON(w, r, &ex) || !s.validExemption(w, segmentID, ex, id) {
+			return
+		}
+
+		ex.ID = id
+		ex.CreatedAt = exemptions[idx].CreatedAt
+		ex.UpdatedAt = s.now().UTC().Truncate(time.Millisecond)
+		exemptions[idx] = ex
+
+	case http.MethodDelete:
+		s.exemptions[segmentID] = slices.Delete(exemptions, idx, idx+1)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+// validExemption checks an exemption about to be saved under id, empty for a
+// new one, and returns false after writing an error response if it is
+// invalid.
+func (s *Server) validExemption(w http.ResponseWriter, segmentID string, ex model.Exemption, id string) bool {
+	if ex.Metric == "" {
+		writeError(w, http.StatusBadRequest, "metric is required")
+		return false
+	}
+
+	for _, other := range s.exemptions[segmentID] {
+		if other.ID != id && other.Metric == ex.Metric {
+			writeError(w, http.StatusConflict, fmt.Sprintf("an exemption for metric %q already exists", ex.Metric))
+			return false
+		}
+	}
+	return true
+}
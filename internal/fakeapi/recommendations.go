@@ -0,0 +1,50 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+func (s *Server) handleRecommendations(w http.ResponseWriter, r *http.Request) {
+	segmentID, ok := s.segmentID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	verbose := query.Get("verbose") == "true"
+	actions := query["action"]
+
+	recs := []model.AggregationRecommendation{}
+	for _, rec := range s.recommendations[segmentID] {
+		if len(actions) > 0 && !slices.Contains(actions, rec.RecommendedAction) {
+			continue
+		}
+		if !verbose {
+			rec = model.AggregationRecommendation{AggregationRule: rec.AggregationRule}
+		}
+		recs = append(recs, rec)
+	}
+
+	writeJSON(w, recs)
+}
+
+func (s *Server) handleRecommendationsConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.recommendationsConfig)
+
+	case http.MethodPost:
+		var config model.AggregationRecommendationConfiguration
+		if !readJSON(w, r, &config) {
+			return
+		}
+		s.recommendationsConfig = config
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
@@ -0,0 +1,145 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
+	segmentID, ok := s.segmentID(w, r)
+	if !ok {
+		return
+	}
+	rs := s.ruleSet(segmentID)
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("ETag", rs.etag())
+		writeJSON(w, nonNilRules(rs.rules))
+
+	case http.MethodPost:
+		if !checkIfMatch(w, r, rs) {
+			return
+		}
+
+		var rules []model.AggregationRule
+		if !readJSON(w, r, &rules) {
+			return
+		}
+		for i, rule := range rules {
+			if !validRule(w, rule) {
+				return
+			}
+			for _, other := range rules[:i] {
+				sameMatchType := other.MatchType == rule.MatchType || (other.IsExactMatch() && rule.IsExactMatch())
+				if other.Metric == rule.Metric && sameMatchType {
+					writeError(w, http.StatusBadRequest, fmt.Sprintf("duplicate aggregation rule for metric %q", rule.Metric))
+					return
+				}
+			}
+		}
+
+		rs.rules = rules
+		rs.version++
+		w.Header().Set("ETag", rs.etag())
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+func (s *Server) handleRule(w http.ResponseWriter, r *http.Request, metric string) {
+	segmentID, ok := s.segmentID(w, r)
+	if !ok {
+		return
+	}
+	rs := s.ruleSet(segmentID)
+
+	idx := slices.IndexFunc(rs.rules, func(rule model.AggregationRule) bool {
+		return rule.Metric == metric
+	})
+
+	if r.Method == http.MethodGet {
+		if idx < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("aggregation rule for metric %q not found", metric))
+			return
+		}
+		w.Header().Set("ETag", rs.etag())
+		writeJSON(w, rs.rules[idx])
+		return
+	}
+
+	if !checkIfMatch(w, r, rs) {
+		return
+	}
+
+	var rule model.AggregationRule
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if !readJSON(w, r, &rule) {
+			return
+		}
+		if rule.Metric != metric {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("metric %q in body does not match metric %q in path", rule.Metric, metric))
+			return
+		}
+		if !validRule(w, rule) {
+			return
+		}
+	}
+
+	switch {
+	case r.Method == http.MethodPost && idx >= 0:
+		writeError(w, http.StatusConflict, fmt.Sprintf("aggregation rule for metric %q already exists", metric))
+		return
+	case r.Method == http.MethodPost:
+		rs.rules = append(rs.rules, rule)
+	case (r.Method == http.MethodPut || r.Method == http.MethodDelete) && idx < 0:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("aggregation rule for metric %q not found", metric))
+		return
+	case r.Method == http.MethodPut:
+		rs.rules[idx] = rule
+	case r.Method == http.MethodDelete:
+		rs.rules = slices.Delete(rs.rules, idx, idx+1)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+		return
+	}
+
+	rs.version++
+	w.Header().Set("ETag", rs.etag())
+}
+
+// checkIfMatch returns false after writing an error response if a write is
+// not guarded by the segment's current ETag.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, rs *ruleSet) bool {
+	if r.Header.Get("If-Match") != rs.etag() {
+		writeError(w, http.StatusPreconditionFailed, "the aggregation rules have been modified since they were last read")
+		return false
+	}
+	return true
+}
+
+func validRule(w http.ResponseWriter, rule model.AggregationRule) bool {
+	switch rule.MatchType {
+	case "", "exact", "prefix", "suffix":
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid match type %q", rule.MatchType))
+		return false
+	}
+
+	if rule.Metric == "" {
+		writeError(w, http.StatusBadRequest, "metric is required")
+		return false
+	}
+	return true
+}
+
+func nonNilRules(rules []model.AggregationRule) []model.AggregationRule {
+	if rules == nil {
+		return []model.AggregationRule{}
+	}
+	return rules
+}
@@ -0,0 +1,105 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+func (s *Server) handleSegments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		segments := s.segments
+		if segments == nil {
+			segments = []model.Segment{}
+		}
+		writeJSON(w, segments)
+
+	case http.MethodPost:
+		var segment model.Segment
+		if !readJSON(w, r, &segment) || !s.validSegment(w, segment, "") {
+			return
+		}
+
+		segment.ID = s.newID()
+		s.segments = append(s.segments, segment)
+		s.rules[segment.ID] = &ruleSet{version: 1}
+		writeJSON(w, segment)
+
+	case http.MethodPut:
+		id := r.URL.Query().Get("segment")
+		idx := s.segmentIndex(id)
+		if idx < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("segment %q not found", id))
+			return
+		}
+
+		var segment model.Segment
+		if !readJSON(w, r, &segment) || !s.validSegment(w, segment, id) {
+			return
+		}
+
+		segment.ID = id
+		s.segments[idx] = segment
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("segment")
+		idx := s.segmentIndex(id)
+		if idx < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("segment %q not found", id))
+			return
+		}
+
+		s.segments = slices.Delete(s.segments, idx, idx+1)
+		delete(s.rules, id)
+		delete(s.exemptions, id)
+		delete(s.recommendations, id)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+// validSegment checks a segment about to be saved under id, empty for a new
+// one, and returns false after writing an error response if it is invalid.
+func (s *Server) validSegment(w http.ResponseWriter, segment model.Segment, id string) bool {
+	switch {
+	case segment.Name == "":
+		writeError(w, http.StatusBadRequest, "segment name is required")
+		return false
+	case segment.Selector == "":
+		writeError(w, http.StatusBadRequest, "segment selector is required")
+		return false
+	}
+
+	for _, other := range s.segments {
+		if other.ID != id && other.Name == segment.Name {
+			writeError(w, http.StatusConflict, fmt.Sprintf("a segment named %q already exists", segment.Name))
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) handleSegmentedRules(w http.ResponseWriter) {
+	segmented := make([]model.SegmentedRuleSet, 0, len(s.segments)+1)
+
+	defaultRules := s.ruleSet("")
+	segmented = append(segmented, model.SegmentedRuleSet{
+		Etag:  defaultRules.etag(),
+		Rules: nonNilRules(defaultRules.rules),
+	})
+
+	for _, segment := range s.segments {
+		rs := s.ruleSet(segment.ID)
+		segmented = append(segmented, model.SegmentedRuleSet{
+			Etag:    rs.etag(),
+			Segment: segment,
+			Rules:   nonNilRules(rs.rules),
+		})
+	}
+
+	writeJSON(w, segmented)
+}
@@ -0,0 +1,193 @@
+// Package fakeapi is a stateful, in-memory implementation of the Adaptive
+// Metrics API, for tests that should run without a Grafana Cloud tenant.
+//
+// It covers the endpoints used by the provider: segments, aggregation rules
+// (with the same ETag and If-Match semantics as the real API), exemptions,
+// recommendations and the recommendations configuration.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+const (
+	segmentsPath              = "/aggregations/rules/segments"
+	segmentedRulesPath        = "/aggregations/segmented_rules"
+	rulesPath                 = "/aggregations/rules"
+	rulePathPrefix            = "/aggregations/rule/"
+	recommendationsPath       = "/aggregations/recommendations"
+	recommendationsConfigPath = "/aggregations/recommendations/config"
+	exemptionsPath            = "/v1/recommendations/exemptions"
+	exemptionPathPrefix       = "/v1/recommendations/exemptions/"
+)
+
+// Server is the fake API. It implements http.Handler, and is usually served
+// with httptest.NewServer. The zero value is not usable; use New.
+type Server struct {
+	mu sync.Mutex
+
+	segments []model.Segment
+	// rules, exemptions and recommendations are keyed by segment ID, the
+	// default segment's being empty.
+	rules                 map[string]*ruleSet
+	exemptions            map[string][]model.Exemption
+	recommendations       map[string][]model.AggregationRecommendation
+	recommendationsConfig model.AggregationRecommendationConfiguration
+
+	lastID int
+	now    func() time.Time
+}
+
+// ruleSet holds a segment's rules along with the version their ETag is
+// derived from. Every write bumps the version.
+type ruleSet struct {
+	version int
+	rules   []model.AggregationRule
+}
+
+func (rs *ruleSet) etag() string {
+	return fmt.Sprintf("%q", fmt.Sprint(rs.version))
+}
+
+// New returns a fake API with an empty default segment.
+func New() *Server {
+	return &Server{
+		rules:           map[string]*ruleSet{"": {version: 1}},
+		exemptions:      make(map[string][]model.Exemption),
+		recommendations: make(map[string][]model.AggregationRecommendation),
+		now:             time.Now,
+	}
+}
+
+// AddSegment adds a segment, generating its ID if it has none, and returns
+// it.
+func (s *Server) AddSegment(segment model.Segment) model.Segment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if segment.ID == "" {
+		segment.ID = s.newID()
+	}
+	s.segments = append(s.segments, segment)
+	s.rules[segment.ID] = &ruleSet{version: 1}
+	return segment
+}
+
+// SetRules replaces a segment's rules, as if they had been edited outside
+// of Terraform.
+func (s *Server) SetRules(segmentID string, rules []model.AggregationRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.ruleSet(segmentID)
+	rs.rules = slices.Clone(rules)
+	rs.version++
+}
+
+// Rules returns a segment's current rules.
+func (s *Server) Rules(segmentID string) []model.AggregationRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.ruleSet(segmentID).rules)
+}
+
+// SetRecommendations sets the recommendations served for a segment. Verbose
+// fields are only returned when requested.
+func (s *Server) SetRecommendations(segmentID string, recs []model.AggregationRecommendation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recommendations[segmentID] = slices.Clone(recs)
+}
+
+// ruleSet returns a segment's rules, creating them if the segment is not
+// known yet. Must be called with s.mu held.
+func (s *Server) ruleSet(segmentID string) *ruleSet {
+	rs, ok := s.rules[segmentID]
+	if !ok {
+		rs = &ruleSet{version: 1}
+		s.rules[segmentID] = rs
+	}
+	return rs
+}
+
+// newID returns an ID shaped like the ULIDs the real API uses. Must be
+// called with s.mu held.
+func (s *Server) newID() string {
+	s.lastID++
+	return fmt.Sprintf("01FAKE%020d", s.lastID)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch p := r.URL.Path; {
+	case p == segmentsPath:
+		s.handleSegments(w, r)
+	case p == segmentedRulesPath && r.Method == http.MethodGet:
+		s.handleSegmentedRules(w)
+	case p == rulesPath:
+		s.handleRules(w, r)
+	case strings.HasPrefix(p, rulePathPrefix):
+		s.handleRule(w, r, strings.TrimPrefix(p, rulePathPrefix))
+	case p == recommendationsPath && r.Method == http.MethodGet:
+		s.handleRecommendations(w, r)
+	case p == recommendationsConfigPath:
+		s.handleRecommendationsConfig(w, r)
+	case p == exemptionsPath:
+		s.handleExemptions(w, r)
+	case strings.HasPrefix(p, exemptionPathPrefix):
+		s.handleExemption(w, r, strings.TrimPrefix(p, exemptionPathPrefix))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no route for %s %s", r.Method, p))
+	}
+}
+
+// segmentID returns the segment a request targets, and false after writing
+// an error response if that segment does not exist.
+func (s *Server) segmentID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := r.URL.Query().Get("segment")
+	if id == "" || s.segmentIndex(id) >= 0 {
+		return id, true
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("segment %q not found", id))
+	return "", false
+}
+
+func (s *Server) segmentIndex(id string) int {
+	return slices.IndexFunc(s.segments, func(segment model.Segment) bool {
+		return segment.ID == id
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// readJSON decodes the request body into v, and returns false after writing
+// an error response if it is malformed.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+	return true
+}
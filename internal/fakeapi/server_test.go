@@ -0,0 +1,160 @@
+package fakeapi
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+func newTestClient(t *testing.T) (*Server, *client.Client) {
+	s := New()
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL, &client.Config{})
+	require.NoError(t, err)
+	return s, c
+}
+
+func TestRules(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestClient(t)
+
+	rules, etag, err := c.ReadAggregationRuleSet(ctx, "")
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	etag, err = c.CreateAggregationRule(ctx, "", model.AggregationRule{Metric: "a", Drop: true}, etag)
+	require.NoError(t, err)
+
+	rule, readEtag, err := c.ReadAggregationRule(ctx, "", "a")
+	require.NoError(t, err)
+	require.Equal(t, model.AggregationRule{Metric: "a", Drop: true}, rule)
+	require.Equal(t, etag, readEtag)
+
+	// Writes with an outdated ETag are rejected.
+	s.SetRules("", []model.AggregationRule{{Metric: "a"}})
+	_, err = c.UpdateAggregationRule(ctx, "", model.AggregationRule{Metric: "a", Aggregations: []string{"sum"}}, etag)
+	require.True(t, client.IsErrPreconditionFailed(err))
+
+	_, etag, err = c.ReadAggregationRuleSet(ctx, "")
+	require.NoError(t, err)
+
+	_, err = c.CreateAggregationRule(ctx, "", model.AggregationRule{Metric: "a"}, etag)
+	require.Error(t, err)
+
+	etag, err = c.UpdateAggregationRule(ctx, "", model.AggregationRule{Metric: "a", Aggregations: []string{"sum"}}, etag)
+	require.NoError(t, err)
+	require.Equal(t, []model.AggregationRule{{Metric: "a", Aggregations: []string{"sum"}}}, s.Rules(""))
+
+	etag, err = c.DeleteAggregationRule(ctx, "", "a", etag)
+	require.NoError(t, err)
+	_, _, err = c.ReadAggregationRule(ctx, "", "a")
+	require.True(t, client.IsErrNotFound(err))
+
+	_, err = c.UpdateAggregationRuleSet(ctx, "", []model.AggregationRule{{Metric: "b"}, {Metric: "b", MatchType: "exact"}}, etag)
+	require.True(t, client.IsErrValidation(err))
+
+	_, err = c.UpdateAggregationRuleSet(ctx, "", []model.AggregationRule{{Metric: "b"}, {Metric: "c", MatchType: "prefix"}}, etag)
+	require.NoError(t, err)
+	require.Equal(t, []model.AggregationRule{{Metric: "b"}, {Metric: "c", MatchType: "prefix"}}, s.Rules(""))
+}
+
+func TestSegments(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestClient(t)
+
+	segment, err := c.CreateSegment(ctx, model.Segment{Name: "test", Selector: `{namespace="test"}`, FallbackToDefault: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, segment.ID)
+
+	_, err = c.CreateSegment(ctx, model.Segment{Name: "test", Selector: `{namespace="other"}`})
+	require.Error(t, err)
+
+	segment.Name = "renamed"
+	require.NoError(t, c.UpdateSegment(ctx, segment))
+	read, err := c.ReadSegment(ctx, segment.ID)
+	require.NoError(t, err)
+	require.Equal(t, segment, read)
+
+	// Each segment has its own rules and ETag.
+	_, etag, err := c.ReadAggregationRuleSet(ctx, segment.ID)
+	require.NoError(t, err)
+	_, err = c.CreateAggregationRule(ctx, segment.ID, model.AggregationRule{Metric: "a"}, etag)
+	require.NoError(t, err)
+	require.Empty(t, s.Rules(""))
+
+	segmented, err := c.SegmentedAggregationRules(ctx)
+	require.NoError(t, err)
+	require.Len(t, segmented, 2)
+	require.Equal(t, segment, segmented[1].Segment)
+	require.Equal(t, []model.AggregationRule{{Metric: "a"}}, segmented[1].Rules)
+
+	require.NoError(t, c.DeleteSegment(ctx, segment.ID))
+	_, err = c.ReadSegment(ctx, segment.ID)
+	require.True(t, client.IsErrNotFound(err))
+	_, _, err = c.ReadAggregationRuleSet(ctx, segment.ID)
+	require.True(t, client.IsErrNotFound(err))
+}
+
+func TestExemptions(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestClient(t)
+
+	ex, err := c.CreateExemption(ctx, "", model.Exemption{Metric: "a", KeepLabels: []string{"namespace"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, ex.ID)
+	require.False(t, ex.CreatedAt.IsZero())
+
+	_, err = c.CreateExemption(ctx, "", model.Exemption{Metric: "a"})
+	require.Error(t, err)
+
+	ex.Reason = "testing"
+	require.NoError(t, c.UpdateExemption(ctx, "", ex))
+	read, err := c.ReadExemption(ctx, "", ex.ID)
+	require.NoError(t, err)
+	require.Equal(t, "testing", read.Reason)
+	require.Equal(t, ex.CreatedAt, read.CreatedAt)
+
+	require.NoError(t, c.DeleteExemption(ctx, "", ex.ID))
+	_, err = c.ReadExemption(ctx, "", ex.ID)
+	require.True(t, client.IsErrNotFound(err))
+}
+
+func TestRecommendations(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestClient(t)
+
+	segment := s.AddSegment(model.Segment{Name: "test", Selector: `{namespace="test"}`})
+	s.SetRecommendations(segment.ID, []model.AggregationRecommendation{
+		{AggregationRule: model.AggregationRule{Metric: "a", Drop: true}, RecommendedAction: "add", UsagesInQueries: 1},
+		{AggregationRule: model.AggregationRule{Metric: "b"}, RecommendedAction: "keep"},
+	})
+
+	recs, err := c.AggregationRecommendations(ctx, segment.ID, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, []model.AggregationRecommendation{
+		{AggregationRule: model.AggregationRule{Metric: "a", Drop: true}},
+		{AggregationRule: model.AggregationRule{Metric: "b"}},
+	}, recs)
+
+	recs, err = c.AggregationRecommendations(ctx, segment.ID, true, []string{"add"})
+	require.NoError(t, err)
+	require.Equal(t, []model.AggregationRecommendation{
+		{AggregationRule: model.AggregationRule{Metric: "a", Drop: true}, RecommendedAction: "add", UsagesInQueries: 1},
+	}, recs)
+
+	recs, err = c.AggregationRecommendations(ctx, "", false, nil)
+	require.NoError(t, err)
+	require.Empty(t, recs)
+
+	require.NoError(t, c.UpdateAggregationRecommendationsConfig(ctx, model.AggregationRecommendationConfiguration{KeepLabels: []string{"namespace"}}))
+	config, err := c.AggregationRecommendationsConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"namespace"}, config.KeepLabels)
+}
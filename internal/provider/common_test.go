@@ -1,63 +1,209 @@
 package provider
 
 import (
+	"errors"
+	"hash/fnv"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/stretchr/testify/require"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/fakeapi"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/recorder"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
-func CheckAccTestsEnabled(t *testing.T) {
+// CheckAccTestsEnabled skips the test unless it can run, and sets up the API
+// it runs against:
+//   - With TF_ACC set, the tenant set by GRAFANA_AM_API_URL and
+//     GRAFANA_AM_API_KEY, recording the interactions into the test's fixture
+//     if GRAFANA_AM_RECORD_FIXTURES is set.
+//   - With TF_ACC set but no tenant, an in-memory fake API, recording the
+//     interactions likewise.
+//   - Without TF_ACC, the interactions recorded in the test's fixture, if it
+//     has one.
+//
+// The returned accTest holds what the test must send its API requests
+// through and name its resources with.
+func CheckAccTestsEnabled(t *testing.T) *accTest {
 	t.Helper()
 
+	acc := &accTest{t: t, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
 	if enabled, _ := strconv.ParseBool(os.Getenv(resource.EnvTfAcc)); enabled {
+		_, hasURL := os.LookupEnv("GRAFANA_AM_API_URL")
+		_, hasKey := os.LookupEnv("GRAFANA_AM_API_KEY")
+		if !hasURL && !hasKey {
+			t.Log("GRAFANA_AM_API_URL and GRAFANA_AM_API_KEY are not set, running against an in-memory fake API.")
+			startFakeAPI(t)
+			if record, _ := strconv.ParseBool(os.Getenv("GRAFANA_AM_RECORD_FIXTURES")); record {
+				recordFixture(t, acc)
+			}
+			return acc
+		}
+
 		for _, env := range []string{"GRAFANA_AM_API_URL", "GRAFANA_AM_API_KEY"} {
 			if _, ok := os.LookupEnv(env); !ok {
 				t.Fatalf("Missing required env var: %s", env)
 			}
 		}
-		return
+
+		if record, _ := strconv.ParseBool(os.Getenv("GRAFANA_AM_RECORD_FIXTURES")); record {
+			recordFixture(t, acc)
+		}
+		return acc
 	}
 
-	t.Skip("Set TF_ACC=true to enable acceptance tests.")
+	replayFixture(t, acc)
+	return acc
+}
+
+// accTest is an acceptance test's view of the API. Each test has its own, so
+// that tests can run in parallel.
+type accTest struct {
+	t *testing.T
+
+	// transport is what API requests are sent through, both from the
+	// provider and from test helpers. Nil means the default one.
+	transport http.RoundTripper
+
+	// rand generates the random parts of test resource names. It is seeded
+	// from the test name when recording or replaying fixtures, so that
+	// replayed requests match the recorded ones.
+	rand *rand.Rand
+}
+
+// seedRand seeds the test's names from its name.
+func (a *accTest) seedRand() {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(a.t.Name()))
+	a.rand = rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+func fixturePath(t *testing.T) string {
+	return filepath.Join("testdata", "fixtures", t.Name()+".json")
+}
+
+// recordFixture records the test's interactions with the API into its
+// fixture, once the test has passed.
+func recordFixture(t *testing.T, acc *accTest) {
+	t.Helper()
+
+	rec := recorder.NewRecorder(fixturePath(t), cleanhttp.DefaultPooledTransport(), os.Getenv("GRAFANA_AM_API_KEY"))
+	t.Cleanup(func() {
+		if !t.Failed() {
+			require.NoError(t, rec.Save())
+		}
+	})
+	acc.transport = rec
+	acc.seedRand()
+}
+
+// replayFixture answers the test's API requests from its fixture, or skips
+// the test if it has none.
+func replayFixture(t *testing.T, acc *accTest) {
+	t.Helper()
+
+	rep, err := recorder.NewReplayer(fixturePath(t), replayAPIKey)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("Set TF_ACC=true to enable acceptance tests.")
+	}
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if !t.Failed() {
+			require.Empty(t, rep.Unused(), "Some recorded interactions were not replayed, re-record %s.", fixturePath(t))
+		}
+	})
+	acc.transport = rep
+	acc.seedRand()
+
+	t.Setenv(resource.EnvTfAcc, "1")
+	t.Setenv("GRAFANA_AM_API_URL", "https://fixtures.invalid")
+	t.Setenv("GRAFANA_AM_API_KEY", replayAPIKey)
+}
+
+// replayAPIKey and fakeAPIKey stand in for a tenant's API key when replaying
+// fixtures and running against the fake API.
+const (
+	replayAPIKey = "replay-tenant:replay-token"
+	fakeAPIKey   = "fake-tenant:fake-token"
+)
+
+// recommendationsTestSegmentID is the segment of the test tenant holding the
+// recommendations the recommendations data source test expects.
+const recommendationsTestSegmentID = "01JQVN6036Z18P6Z958JNNTXRP"
+
+// startFakeAPI serves a fake API for the rest of the test, seeded with the
+// same fixtures as the test tenant, and points the provider at it.
+func startFakeAPI(t *testing.T) *fakeapi.Server {
+	t.Helper()
+
+	api := fakeapi.New()
+	api.AddSegment(model.Segment{
+		ID:                recommendationsTestSegmentID,
+		Name:              "terraform provider acceptance tests",
+		Selector:          `{am_terraform_provider_acceptance_test="true"}`,
+		FallbackToDefault: true,
+	})
+	api.SetRecommendations(recommendationsTestSegmentID, []model.AggregationRecommendation{{
+		AggregationRule: model.AggregationRule{
+			Metric:       "am_terraform_provider_acceptance_test_metric",
+			DropLabels:   []string{"this", "metric", "doesnt", "exist"},
+			Aggregations: []string{"count"},
+		},
+		RecommendedAction: "keep",
+	}})
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	t.Setenv("GRAFANA_AM_API_URL", server.URL)
+	t.Setenv("GRAFANA_AM_API_KEY", fakeAPIKey)
+	return api
 }
 
 const letters = "abcdefghijklmnopqrstuvwxyz"
 
-func RandString(n int) string {
+func (a *accTest) RandString(n int) string {
 	b := make([]byte, n)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[a.rand.Intn(len(letters))]
 	}
 	return string(b)
 }
 
-func ClientForAccTest(t *testing.T) *client.Client {
-	t.Helper()
+func (a *accTest) Client() *client.Client {
+	a.t.Helper()
 
 	apiURL := os.Getenv("GRAFANA_AM_API_URL")
 	apiKey := os.Getenv("GRAFANA_AM_API_KEY")
 
 	c, err := client.New(apiURL, &client.Config{
-		APIKey: apiKey,
+		APIKey:     apiKey,
+		HttpClient: &http.Client{Transport: a.transport},
 	})
-	require.NoError(t, err)
+	require.NoError(a.t, err)
 
 	return c
 }
 
-func AggregationRulesForAccTest(t *testing.T) *AggregationRules {
-	t.Helper()
+func (a *accTest) AggregationRules() *AggregationRules {
+	a.t.Helper()
 
-	c := ClientForAccTest(t)
-
-	aggRules := NewAggregationRules(c)
-	require.NoError(t, aggRules.Init())
+	return NewAggregationRules(a.Client())
+}
 
-	return aggRules
+func (a *accTest) ProviderFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return testAccProtoV6ProviderFactories(a.transport)
 }
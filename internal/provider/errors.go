@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+)
+
+// errorDetail renders an API error as a diagnostic detail. Errors the user can
+// act on get an explanation in front of the raw error.
+func errorDetail(err error) string {
+	var (
+		validationErr  client.ErrValidation
+		rateLimitedErr client.ErrRateLimited
+		conflictErr    ErrRuleConflict
+	)
+
+	switch {
+	case errors.As(err, &conflictErr):
+		return fmt.Sprintf("The %s. Refresh the state and review the change before applying again, or import the rule to manage it with Terraform.", conflictErr)
+	case errors.As(err, &validationErr):
+		return fmt.Sprintf("The Adaptive Metrics API rejected the request: %s", validationErr.Message)
+	case client.IsErrUnauthorized(err):
+		return fmt.Sprintf("The Adaptive Metrics API rejected the configured credentials. Check that `api_key` (or `GRAFANA_AM_API_KEY`) is set to '<tenant-id>:<token>' and that the token has not expired.\n\n%s", err)
+	case client.IsErrForbidden(err):
+		return fmt.Sprintf("The configured credentials are not allowed to perform this operation. Check the scopes of the access policy the token belongs to.\n\n%s", err)
+	case client.IsErrPreconditionFailed(err):
+		return fmt.Sprintf("The aggregation rules in this segment were modified outside of this Terraform run. Refresh the state and apply again.\n\n%s", err)
+	case errors.As(err, &rateLimitedErr):
+		if rateLimitedErr.RetryAfter > 0 {
+			return fmt.Sprintf("The Adaptive Metrics API is rate limiting requests; retry in %s or lower Terraform's parallelism.\n\n%s", rateLimitedErr.RetryAfter, err)
+		}
+		return fmt.Sprintf("The Adaptive Metrics API is rate limiting requests; retry later or lower Terraform's parallelism.\n\n%s", err)
+	case client.IsErrAmbiguousWrite(err):
+		// Checked before server errors, which an ambiguous write may wrap:
+		// retrying a write that was applied can create duplicates.
+		return fmt.Sprintf("The request failed in a way that doesn't tell whether the Adaptive Metrics API applied it. Check whether the object was created or changed, and import it if it exists, before retrying the operation.\n\n%s", err)
+	case client.IsErrServerError(err):
+		return fmt.Sprintf("The Adaptive Metrics API failed to handle the request. This is usually transient; retry the operation.\n\n%s", err)
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+)
+
+func TestErrorDetail(t *testing.T) {
+	require.Equal(t, "boom", errorDetail(errors.New("boom")))
+
+	require.Equal(t,
+		"The Adaptive Metrics API rejected the request: unknown aggregation \"sum:countr\"",
+		errorDetail(client.ErrValidation{StatusCode: 400, Message: `unknown aggregation "sum:countr"`}),
+	)
+
+	require.Contains(t, errorDetail(client.ErrUnauthorized{}), "GRAFANA_AM_API_KEY")
+	require.Contains(t, errorDetail(client.ErrPreconditionFailed{}), "modified outside of this Terraform run")
+	require.Contains(t, errorDetail(client.ErrRateLimited{RetryAfter: 5 * time.Second}), "retry in 5s")
+
+	ambiguous := client.ErrAmbiguousWrite{Method: "POST", Path: "/v1/recommendations/exemptions", Err: client.ErrServerError{StatusCode: 502}}
+	require.Contains(t, errorDetail(ambiguous), "import it if it exists, before retrying")
+	require.NotContains(t, errorDetail(ambiguous), "usually transient")
+	require.Contains(t, errorDetail(client.ErrServerError{StatusCode: 502}), "usually transient")
+}
@@ -16,8 +16,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 type exemptionResource struct {
@@ -112,9 +112,9 @@ func (e *exemptionResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	ex, err := e.client.CreateExemption(plan.Segment.ValueString(), plan.ToAPIReq())
+	ex, err := e.client.CreateExemption(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to create exemption", err.Error())
+		resp.Diagnostics.AddError("Unable to create exemption", errorDetail(err))
 		return
 	}
 
@@ -132,14 +132,14 @@ func (e *exemptionResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	ex, err := e.client.ReadExemption(state.Segment.ValueString(), state.ID.ValueString())
+	ex, err := e.client.ReadExemption(ctx, state.Segment.ValueString(), state.ID.ValueString())
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			resp.Diagnostics.AddWarning("Exemption not found", err.Error())
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Unable to read exemption", err.Error())
+		resp.Diagnostics.AddError("Unable to read exemption", errorDetail(err))
 		return
 	}
 
@@ -166,15 +166,15 @@ func (e *exemptionResource) Update(ctx context.Context, req resource.UpdateReque
 	ex := plan.ToAPIReq()
 	ex.ID = state.ID.ValueString()
 
-	err := e.client.UpdateExemption(state.Segment.ValueString(), ex)
+	err := e.client.UpdateExemption(ctx, state.Segment.ValueString(), ex)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update exemption", err.Error())
+		resp.Diagnostics.AddError("Unable to update exemption", errorDetail(err))
 		return
 	}
 
-	ex, err = e.client.ReadExemption(state.Segment.ValueString(), state.ID.ValueString())
+	ex, err = e.client.ReadExemption(ctx, state.Segment.ValueString(), state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to read exemption after updating", err.Error())
+		resp.Diagnostics.AddError("Unable to read exemption after updating", errorDetail(err))
 		return
 	}
 
@@ -191,12 +191,12 @@ func (e *exemptionResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	err := e.client.DeleteExemption(state.Segment.ValueString(), state.ID.ValueString())
+	err := e.client.DeleteExemption(ctx, state.Segment.ValueString(), state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete exemption", err.Error())
+		resp.Diagnostics.AddError("Unable to delete exemption", errorDetail(err))
 	}
 }
 
 func (e *exemptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	importSegmentedID(ctx, path.Root("id"), req, resp)
 }
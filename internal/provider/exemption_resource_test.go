@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -9,11 +10,11 @@ import (
 )
 
 func TestAccExemptionResource(t *testing.T) {
-	CheckAccTestsEnabled(t)
+	acc := CheckAccTestsEnabled(t)
 
 	var exemptionID string
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
 		Steps: []resource.TestStep{
 			// Create + Read.
 			{
@@ -39,6 +40,16 @@ resource "grafana-adaptive-metrics_exemption" "test" {
 				// import.
 				ImportStateVerifyIgnore: []string{"last_updated"},
 			},
+			// ImportState with the default segment spelled out.
+			{
+				ResourceName:      "grafana-adaptive-metrics_exemption.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "default/" + s.RootModule().Resources["grafana-adaptive-metrics_exemption.test"].Primary.ID, nil
+				},
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
 			// Update + Read.
 			{
 				Config: providerConfig + `
@@ -64,8 +75,8 @@ resource "grafana-adaptive-metrics_exemption" "test" {
 			// External delete of resource, TF should recreate it.
 			{
 				PreConfig: func() {
-					client := ClientForAccTest(t)
-					require.NoError(t, client.DeleteExemption("", exemptionID))
+					client := acc.Client()
+					require.NoError(t, client.DeleteExemption(context.Background(), "", exemptionID))
 				},
 				Config: providerConfig + `
 resource "grafana-adaptive-metrics_exemption" "test" {
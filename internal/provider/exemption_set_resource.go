@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type exemptionSetResource struct {
+	client *client.Client
+}
+
+var (
+	_ resource.Resource                   = &exemptionSetResource{}
+	_ resource.ResourceWithConfigure      = &exemptionSetResource{}
+	_ resource.ResourceWithImportState    = &exemptionSetResource{}
+	_ resource.ResourceWithValidateConfig = &exemptionSetResource{}
+)
+
+func newExemptionSetResource() resource.Resource {
+	return &exemptionSetResource{}
+}
+
+func (e *exemptionSetResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource configure type",
+			fmt.Sprintf("Got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = data.client
+}
+
+func (e *exemptionSetResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_exemption_set", req.ProviderTypeName)
+}
+
+func (e *exemptionSetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages all recommendation exemptions of a segment. Exemptions of the segment that aren't in the set are deleted.",
+		Attributes: map[string]schema.Attribute{
+			"segment": schema.StringAttribute{
+				Optional:    true,
+				Description: "The id of the segment to manage exemptions for.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ignore_unmanaged": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When set to true, exemptions whose managed_by isn't 'terraform', such as exemptions created in the UI, are neither read into the set nor deleted. Exemptions in the set always become managed by Terraform.",
+			},
+			"exemptions": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"metric": schema.StringAttribute{
+							Required:    true,
+							Description: "The name of the metric to exempt. Each metric may only appear once in the set.",
+							Validators:  []validator.String{nonEmptyString()},
+						},
+						"keep_labels": schema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Computed:    true,
+							Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+							Description: "The array of labels to keep; labels not in this array will be aggregated.",
+						},
+						"disable_recommendations": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "When set to true, the recommendations service will exempt this metric from consideration.",
+						},
+						"reason": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(""),
+							Description: "An optional string detailing the reason(s) for this exemption.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig rejects exemption sets that exempt a metric more than once,
+// since exemptions of a set are matched to upstream ones by metric.
+func (e *exemptionSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var exemptionsList types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("exemptions"), &exemptionsList)...)
+	if resp.Diagnostics.HasError() || exemptionsList.IsNull() || exemptionsList.IsUnknown() {
+		return
+	}
+
+	// Only metric is read, since keep_labels may not be known until apply.
+	var exemptions []types.Object
+	resp.Diagnostics.Append(exemptionsList.ElementsAs(ctx, &exemptions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]int, len(exemptions))
+	for i, ex := range exemptions {
+		metricValue, _ := ex.Attributes()["metric"].(types.String)
+		if ex.IsUnknown() || metricValue.IsNull() || metricValue.IsUnknown() {
+			continue
+		}
+		metric := metricValue.ValueString()
+		if first, ok := seen[metric]; ok {
+			resp.Diagnostics.AddAttributeError(path.Root("exemptions").AtListIndex(i).AtName("metric"), "Duplicate exemption",
+				fmt.Sprintf("exemptions[%d] exempts %q, as does exemptions[%d]. An exemption set may only contain one exemption per metric.", i, metric, first))
+			continue
+		}
+		seen[metric] = i
+	}
+}
+
+func (e *exemptionSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan model.ExemptionSetTF
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The set owns the whole segment, so existing exemptions are converged
+	// rather than rejected.
+	if err := e.converge(ctx, plan); err != nil {
+		resp.Diagnostics.AddError("Unable to update exemption set", errorDetail(err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (e *exemptionSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state model.ExemptionSetTF
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported sets don't have a value for the attribute yet.
+	if state.IgnoreUnmanaged.IsNull() {
+		state.IgnoreUnmanaged = types.BoolValue(false)
+	}
+
+	exemptions, err := e.client.ListExemptions(ctx, state.Segment.ValueString())
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			resp.Diagnostics.AddWarning("Exemption set not found", err.Error())
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Unable to read exemption set", errorDetail(err))
+		return
+	}
+
+	var tracked []model.Exemption
+	for _, ex := range exemptions {
+		if state.IgnoreUnmanaged.ValueBool() && !ex.IsManaged() {
+			continue
+		}
+		tracked = append(tracked, ex)
+	}
+
+	// Prevent unnecessary drift due to reordering.
+	tracked = model.AlignExemptionsWithState(state.ToAPIReq(), tracked)
+
+	state.Exemptions = make([]model.ExemptionSetExemptionTF, len(tracked))
+	for i, ex := range tracked {
+		state.Exemptions[i] = ex.ToExemptionSetTF()
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (e *exemptionSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan model.ExemptionSetTF
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := e.converge(ctx, plan); err != nil {
+		resp.Diagnostics.AddError("Unable to update exemption set", errorDetail(err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (e *exemptionSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state model.ExemptionSetTF
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Exemptions = nil
+	if err := e.converge(ctx, state); err != nil {
+		resp.Diagnostics.AddError("Unable to delete exemption set", errorDetail(err))
+	}
+}
+
+// converge creates, updates and deletes the exemptions of the set's segment
+// until they match the set.
+func (e *exemptionSetResource) converge(ctx context.Context, set model.ExemptionSetTF) error {
+	segment := set.Segment.ValueString()
+
+	upstream, err := e.client.ListExemptions(ctx, segment)
+	if err != nil {
+		return err
+	}
+
+	changes := model.DiffExemptions(upstream, set.ToAPIReq(), set.IgnoreUnmanaged.ValueBool())
+
+	for _, ex := range changes.Delete {
+		if err := e.client.DeleteExemption(ctx, segment, ex.ID); err != nil && !client.IsErrNotFound(err) {
+			return fmt.Errorf("deleting exemption for %q: %w", ex.Metric, err)
+		}
+	}
+	for _, ex := range changes.Update {
+		if err := e.client.UpdateExemption(ctx, segment, ex); err != nil {
+			return fmt.Errorf("updating exemption for %q: %w", ex.Metric, err)
+		}
+	}
+	for _, ex := range changes.Create {
+		if _, err := e.client.CreateExemption(ctx, segment, ex); err != nil {
+			return fmt.Errorf("creating exemption for %q: %w", ex.Metric, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportState implements resource.ResourceWithImportState.
+func (e *exemptionSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// As for rulesets, the default segment is imported with the "default" ID.
+	if req.ID == "default" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("segment"), types.StringNull())...)
+	} else {
+		resource.ImportStatePassthroughID(ctx, path.Root("segment"), req, resp)
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/fakeapi"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+func TestAccExemptionSetResource(t *testing.T) {
+	acc := CheckAccTestsEnabled(t)
+
+	metricName := fmt.Sprintf("test_tf_metric_%s", acc.RandString(6))
+	t.Cleanup(func() {
+		c := acc.Client()
+		exemptions, err := c.ListExemptions(context.Background(), "")
+		require.NoError(t, err)
+		for _, ex := range exemptions {
+			if strings.HasPrefix(ex.Metric, metricName) {
+				_ = c.DeleteExemption(context.Background(), "", ex.ID)
+			}
+		}
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
+		Steps: []resource.TestStep{
+			// Create + Read.
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "grafana-adaptive-metrics_exemption_set" "test" {
+	exemptions = [{
+		metric = "%[1]s_a"
+		keep_labels = ["namespace"]
+	}, {
+		metric = "%[1]s_b"
+		disable_recommendations = true
+		reason = "testing"
+	}]
+}
+`, metricName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "ignore_unmanaged", "false"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.#", "2"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.0.metric", metricName+"_a"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.0.keep_labels.0", "namespace"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.1.metric", metricName+"_b"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.1.disable_recommendations", "true"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.1.reason", "testing"),
+				),
+			},
+			// An exemption created outside of Terraform is detected as drift.
+			{
+				PreConfig: func() {
+					_, err := acc.Client().CreateExemption(context.Background(), "", model.Exemption{Metric: metricName + "_ui", ManagedBy: "ui"})
+					require.NoError(t, err)
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.#", "3"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.2.metric", metricName+"_ui"),
+				),
+			},
+			// Ignore it, and update + remove an exemption.
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "grafana-adaptive-metrics_exemption_set" "test" {
+	ignore_unmanaged = true
+	exemptions = [{
+		metric = "%[1]s_b"
+		reason = "updated"
+	}]
+}
+`, metricName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.#", "1"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.0.metric", metricName+"_b"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.0.reason", "updated"),
+					resource.TestCheckResourceAttr("grafana-adaptive-metrics_exemption_set.test", "exemptions.0.disable_recommendations", "false"),
+				),
+			},
+			// ImportState.
+			{
+				ResourceName:  "grafana-adaptive-metrics_exemption_set.test",
+				ImportState:   true,
+				ImportStateId: "default",
+				// We can't use ImportStateVerify because imported sets don't
+				// ignore unmanaged exemptions, so they also track the UI one.
+				ImportStateCheck: func(is []*terraform.InstanceState) error {
+					if len(is) != 1 {
+						return fmt.Errorf("expected 1 state, got %d", len(is))
+					}
+
+					set := is[0].Attributes
+					if set["exemptions.#"] != "2" {
+						return fmt.Errorf("expected 2 exemptions, got %s", set["exemptions.#"])
+					}
+					if set["exemptions.0.metric"] != metricName+"_b" {
+						return fmt.Errorf("expected metric %s_b, got %s", metricName, set["exemptions.0.metric"])
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestExemptionSetConverge(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(fakeapi.New())
+	t.Cleanup(server.Close)
+	c, err := client.New(server.URL, &client.Config{})
+	require.NoError(t, err)
+
+	for _, ex := range []model.Exemption{
+		{Metric: "stale", Reason: "old", ManagedBy: "terraform"},
+		{Metric: "removed", ManagedBy: "terraform"},
+		{Metric: "from_ui", ManagedBy: "ui"},
+	} {
+		_, err := c.CreateExemption(ctx, "", ex)
+		require.NoError(t, err)
+	}
+
+	set := model.ExemptionSetTF{
+		Segment:         types.StringNull(),
+		IgnoreUnmanaged: types.BoolValue(true),
+		Exemptions: []model.ExemptionSetExemptionTF{
+			{Metric: types.StringValue("stale"), Reason: types.StringValue("new")},
+			{Metric: types.StringValue("added"), DisableRecommendations: types.BoolValue(true)},
+		},
+	}
+	r := &exemptionSetResource{client: c}
+	require.NoError(t, r.converge(ctx, set))
+
+	exemptions, err := c.ListExemptions(ctx, "")
+	require.NoError(t, err)
+	got := map[string]model.Exemption{}
+	for _, ex := range exemptions {
+		got[ex.Metric] = ex
+	}
+	require.Len(t, got, 3)
+	require.Equal(t, "new", got["stale"].Reason)
+	require.True(t, got["added"].DisableRecommendations)
+	require.Equal(t, "terraform", got["added"].ManagedBy)
+	require.Equal(t, "ui", got["from_ui"].ManagedBy)
+
+	// Converging again changes nothing.
+	require.NoError(t, r.converge(ctx, set))
+	again, err := c.ListExemptions(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, exemptions, again)
+
+	// Without ignore_unmanaged, the set takes over the whole segment.
+	set.IgnoreUnmanaged = types.BoolValue(false)
+	set.Exemptions = nil
+	require.NoError(t, r.converge(ctx, set))
+	exemptions, err = c.ListExemptions(ctx, "")
+	require.NoError(t, err)
+	require.Empty(t, exemptions)
+}
+
+func TestExemptionSetValidateConfig(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	(&exemptionSetResource{}).Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+	s := schemaResp.Schema
+	exemptionType := s.Attributes["exemptions"].GetType().TerraformType(ctx).(tftypes.List).ElementType.(tftypes.Object)
+
+	var exemptions []tftypes.Value
+	for _, metric := range []string{"up", "down", "up"} {
+		values := map[string]tftypes.Value{}
+		for name, typ := range exemptionType.AttributeTypes {
+			values[name] = tftypes.NewValue(typ, nil)
+		}
+		values["metric"] = tfString(metric)
+		// keep_labels computed from other resources isn't known at plan time.
+		values["keep_labels"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue)
+		exemptions = append(exemptions, tftypes.NewValue(exemptionType, values))
+	}
+	config := tfsdk.Config{
+		Schema: s,
+		Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"segment":          tftypes.NewValue(tftypes.String, nil),
+			"ignore_unmanaged": tftypes.NewValue(tftypes.Bool, nil),
+			"exemptions":       tftypes.NewValue(tftypes.List{ElementType: exemptionType}, exemptions),
+		}),
+	}
+
+	var resp fwresource.ValidateConfigResponse
+	(&exemptionSetResource{}).ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: config}, &resp)
+
+	require.Len(t, resp.Diagnostics, 1, resp.Diagnostics)
+	require.Equal(t, path.Root("exemptions").AtListIndex(2).AtName("metric"), resp.Diagnostics[0].(diag.DiagnosticWithPath).Path())
+	require.Contains(t, resp.Diagnostics[0].Detail(), `exemptions[2] exempts "up", as does exemptions[0]`)
+}
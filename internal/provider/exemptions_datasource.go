@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type exemptionsDatasource struct {
+	client *client.Client
+}
+
+var (
+	_ datasource.DataSource              = &exemptionsDatasource{}
+	_ datasource.DataSourceWithConfigure = &exemptionsDatasource{}
+)
+
+func newExemptionsDatasource() datasource.DataSource {
+	return &exemptionsDatasource{}
+}
+
+func (d *exemptionsDatasource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected datasource configure type",
+			fmt.Sprintf("Got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data
+}
+
+func (d *exemptionsDatasource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_exemptions", req.ProviderTypeName)
+}
+
+func (d *exemptionsDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the recommendation exemptions of a segment, including exemptions created outside of Terraform.",
+		Attributes: map[string]schema.Attribute{
+			"segment": schema.StringAttribute{
+				Optional:    true,
+				Description: "The id of the segment to list exemptions for.",
+			},
+			"metric": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list exemptions for this metric.",
+			},
+			"managed_by": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list exemptions managed by this tool, e.g. 'terraform'.",
+			},
+			"exemptions": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "A ULID that uniquely identifies the exemption.",
+						},
+						"metric": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the metric the exemption applies to.",
+						},
+						"keep_labels": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "The array of labels to keep; labels not in this array will be aggregated.",
+						},
+						"disable_recommendations": schema.BoolAttribute{
+							Computed:    true,
+							Description: "When true, the recommendations service exempts this metric from consideration.",
+						},
+						"reason": schema.StringAttribute{
+							Computed:    true,
+							Description: "The reason(s) for this exemption.",
+						},
+						"managed_by": schema.StringAttribute{
+							Computed:    true,
+							Description: "Who manages the exemption, e.g. 'terraform' for exemptions written by this provider.",
+						},
+						"created_at": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Unix timestamp in milliseconds of when this exemption was created.",
+						},
+						"updated_at": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Unix timestamp in milliseconds of when this exemption was last updated.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *exemptionsDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state model.ExemptionListTF
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	exemptions, err := d.client.ListExemptions(ctx, state.Segment.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to list exemptions", errorDetail(err))
+		return
+	}
+
+	state.Exemptions = []model.ExemptionDataTF{}
+	for _, ex := range exemptions {
+		if state.Matches(ex) {
+			state.Exemptions = append(state.Exemptions, ex.ToDataTF())
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
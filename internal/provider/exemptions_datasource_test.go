@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccExemptionsDatasource(t *testing.T) {
+	acc := CheckAccTestsEnabled(t)
+
+	metricName := fmt.Sprintf("test_tf_metric_%s", acc.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "grafana-adaptive-metrics_exemption" "test" {
+	metric = "%s"
+	keep_labels = ["namespace"]
+	reason = "kept for alerting"
+}
+
+data "grafana-adaptive-metrics_exemptions" "test" {
+	metric = grafana-adaptive-metrics_exemption.test.metric
+	managed_by = "terraform"
+}
+`, metricName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_exemptions.test", "exemptions.#", "1"),
+					resource.TestCheckResourceAttrPair("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.id", "grafana-adaptive-metrics_exemption.test", "id"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.metric", metricName),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.keep_labels.0", "namespace"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.reason", "kept for alerting"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.managed_by", "terraform"),
+					resource.TestCheckResourceAttrSet("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.created_at"),
+					resource.TestCheckResourceAttrSet("data.grafana-adaptive-metrics_exemptions.test", "exemptions.0.updated_at"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+// TestReplayWithoutCredentials replays fixtures recorded against the fake
+// API, with no tenant or credentials in the environment. To re-record them,
+// run it with TF_ACC=true and GRAFANA_AM_RECORD_FIXTURES=true.
+func TestReplayWithoutCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	start := func(t *testing.T) *accTest {
+		if record, _ := strconv.ParseBool(os.Getenv("GRAFANA_AM_RECORD_FIXTURES")); record {
+			return CheckAccTestsEnabled(t)
+		}
+
+		for _, env := range []string{resource.EnvTfAcc, "GRAFANA_AM_API_URL", "GRAFANA_AM_API_KEY"} {
+			t.Setenv(env, "")
+			require.NoError(t, os.Unsetenv(env))
+		}
+		require.FileExists(t, fixturePath(t))
+		return CheckAccTestsEnabled(t)
+	}
+
+	t.Run("rule", func(t *testing.T) {
+		acc := start(t)
+		aggRules := acc.AggregationRules()
+
+		rule := model.AggregationRule{Metric: "test_tf_metric_" + acc.RandString(6), DropLabels: []string{"pod"}, Aggregations: []string{"sum"}}
+		require.NoError(t, aggRules.Create(ctx, "", rule))
+
+		rule.DropLabels = []string{"pod", "instance"}
+		require.NoError(t, aggRules.Update(ctx, "", rule))
+
+		got, err := aggRules.Read(ctx, "", rule.Metric)
+		require.NoError(t, err)
+		require.Equal(t, rule.DropLabels, got.DropLabels)
+
+		require.NoError(t, aggRules.Delete(ctx, "", rule))
+	})
+
+	t.Run("ruleset", func(t *testing.T) {
+		acc := start(t)
+		aggRules := acc.AggregationRules()
+
+		rules := model.AggregationRuleSet{
+			{Metric: "test_tf_metric_" + acc.RandString(6), Drop: true},
+			{Metric: "test_tf_metric_" + acc.RandString(6), DropLabels: []string{"pod"}, Aggregations: []string{"sum"}},
+		}
+		require.NoError(t, aggRules.UpdateRuleSet(ctx, "", rules))
+
+		got, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, ruleMetrics(rules), ruleMetrics(got))
+
+		require.NoError(t, aggRules.UpdateRuleSet(ctx, "", nil))
+	})
+
+	t.Run("exemption", func(t *testing.T) {
+		acc := start(t)
+		c := acc.Client()
+
+		ex, err := c.CreateExemption(ctx, "", model.Exemption{Metric: "test_tf_metric_" + acc.RandString(6), KeepLabels: []string{"pod"}})
+		require.NoError(t, err)
+
+		ex.Reason = "replayed"
+		require.NoError(t, c.UpdateExemption(ctx, "", ex))
+
+		got, err := c.ReadExemption(ctx, "", ex.ID)
+		require.NoError(t, err)
+		require.Equal(t, "replayed", got.Reason)
+
+		require.NoError(t, c.DeleteExemption(ctx, "", ex.ID))
+	})
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// importSegmentedID imports a resource that lives in a segment from an ID of
+// the form "<segment_id>/<id>", storing id in idAttr. As for rulesets, a
+// segment of "default" stands for the default segment. IDs without a "/" are
+// imported from the default segment.
+func importSegmentedID(ctx context.Context, idAttr path.Path, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	segment, id, ok := strings.Cut(req.ID, "/")
+	if !ok {
+		resource.ImportStatePassthroughID(ctx, idAttr, req, resp)
+		return
+	}
+
+	if segment == "" || id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected import identifier",
+			fmt.Sprintf("Expected an import identifier of the form <segment_id>/<%s> or <%[1]s>, got: %q", idAttr, req.ID),
+		)
+		return
+	}
+
+	segmentValue := types.StringValue(segment)
+	if segment == "default" {
+		segmentValue = types.StringNull()
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("segment"), segmentValue)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, idAttr, id)...)
+}
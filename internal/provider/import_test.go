@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportSegmentedID(t *testing.T) {
+	resources := []struct {
+		name     string
+		resource resource.ResourceWithImportState
+		idAttr   string
+	}{
+		{"rule", &ruleResource{}, "metric"},
+		{"exemption", &exemptionResource{}, "id"},
+	}
+
+	tests := []struct {
+		name        string
+		importID    string
+		wantSegment types.String
+		wantID      string
+		wantErr     bool
+	}{
+		{name: "id only", importID: "abc", wantSegment: types.StringNull(), wantID: "abc"},
+		{name: "default segment", importID: "default/abc", wantSegment: types.StringNull(), wantID: "abc"},
+		{name: "named segment", importID: "01HZSEGMENT/abc", wantSegment: types.StringValue("01HZSEGMENT"), wantID: "abc"},
+		{name: "missing segment", importID: "/abc", wantErr: true},
+		{name: "missing id", importID: "01HZSEGMENT/", wantErr: true},
+	}
+
+	for _, r := range resources {
+		for _, tt := range tests {
+			t.Run(r.name+"/"+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+
+				var schemaResp resource.SchemaResponse
+				r.resource.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+				require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+
+				resp := resource.ImportStateResponse{
+					State: tfsdk.State{
+						Schema: schemaResp.Schema,
+						Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+					},
+				}
+				r.resource.ImportState(ctx, resource.ImportStateRequest{ID: tt.importID}, &resp)
+
+				require.Equal(t, tt.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+				if tt.wantErr {
+					return
+				}
+
+				var segment, id types.String
+				require.False(t, resp.State.GetAttribute(ctx, path.Root("segment"), &segment).HasError())
+				require.False(t, resp.State.GetAttribute(ctx, path.Root(r.idAttr), &id).HasError())
+				require.Equal(t, tt.wantSegment, segment)
+				require.Equal(t, tt.wantID, id.ValueString())
+			})
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+)
+
+// clientLogSubsystem is the tflog subsystem API requests are logged under.
+// Its level defaults to the provider's, and can be set on its own through the
+// TF_LOG_PROVIDER_GRAFANA_ADAPTIVE_METRICS_CLIENT environment variable.
+const clientLogSubsystem = "client"
+
+// clientLogger is the client.Logger logging API requests through tflog.
+// tflog loggers live in contexts, so the subsystem is added to the context of
+// each request, but only if the context doesn't carry it yet.
+type clientLogger struct {
+	options tflog.Options
+}
+
+var _ client.Logger = clientLogger{}
+
+// clientLoggerKey marks contexts the client subsystem was set up in.
+type clientLoggerKey struct{}
+
+// newClientLogger returns a clientLogger, building the subsystem's options
+// once for all requests.
+func newClientLogger() clientLogger {
+	return clientLogger{options: tflog.Options{
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_GRAFANA_ADAPTIVE_METRICS", "CLIENT"),
+	}}
+}
+
+func (l clientLogger) RequestContext(ctx context.Context) context.Context {
+	if ctx.Value(clientLoggerKey{}) != nil {
+		return ctx
+	}
+	ctx = tflog.NewSubsystem(ctx, clientLogSubsystem, l.options...)
+	return context.WithValue(ctx, clientLoggerKey{}, true)
+}
+
+func (clientLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	tflog.SubsystemDebug(ctx, clientLogSubsystem, msg, fields)
+}
+
+// logRetry is a retryablehttp.RequestLogHook that logs retries of API
+// requests under the client's log subsystem.
+func logRetry(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	if attempt == 0 {
+		return
+	}
+
+	tflog.SubsystemDebug(req.Context(), clientLogSubsystem, "Retrying API request", map[string]interface{}{
+		"method":  req.Method,
+		"path":    req.URL.Path,
+		"segment": req.URL.Query().Get("segment"),
+		"attempt": attempt,
+	})
+}
@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLoggerSetsUpSubsystemOnce(t *testing.T) {
+	l := newClientLogger()
+
+	ctx := l.RequestContext(context.Background())
+	require.NotNil(t, ctx.Value(clientLoggerKey{}))
+
+	// A context that was already set up is used as it is.
+	require.True(t, ctx == l.RequestContext(ctx))
+}
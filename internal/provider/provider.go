@@ -7,10 +7,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
+	"time"
 
-	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -18,7 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
 )
 
 const privatePreviewWarning = "WARNING: contact Grafana Cloud support before use. This feature is in private preview and may change without notice, including in ways that may break your configuration. "
@@ -37,6 +38,10 @@ type AdaptiveMetricsProvider struct {
 	// provider is built and ran locally or when running acceptance
 	// testing.
 	commit string
+
+	// transport, when set, replaces the transport built from the provider
+	// configuration. Tests use it to record and replay API interactions.
+	transport http.RoundTripper
 }
 
 // AdaptiveMetricsProviderModel describes the provider data model.
@@ -46,6 +51,20 @@ type AdaptiveMetricsProviderModel struct {
 	HTTPHeaders types.Map    `tfsdk:"http_headers"`
 	Retries     types.Int64  `tfsdk:"retries"`
 	Debug       types.Bool   `tfsdk:"debug"`
+
+	DebugBodyMaxBytes types.Int64 `tfsdk:"debug_body_max_bytes"`
+
+	RuleBatchWindow types.String `tfsdk:"rule_batch_window"`
+
+	CACert             types.String `tfsdk:"ca_cert"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+
+	MaxRequestsPerSecond types.Float64 `tfsdk:"max_requests_per_second"`
+	RetryWaitMin         types.String  `tfsdk:"retry_wait_min"`
+	RetryWaitMax         types.String  `tfsdk:"retry_wait_max"`
 }
 
 func getStringOverriddenByEnvOrDefault(s types.String, envKey string, valDefault string) string {
@@ -87,6 +106,28 @@ func getBooleanOverriddenByEnvOrDefault(s types.Bool, envKey string, valDefault
 	return valDefault, nil
 }
 
+func getFloatOverriddenByEnvOrDefault(s types.Float64, envKey string, valDefault float64) (float64, error) {
+	val, ok := os.LookupEnv(envKey)
+	if ok {
+		return strconv.ParseFloat(val, 64)
+	}
+
+	if !s.IsNull() {
+		return s.ValueFloat64(), nil
+	}
+
+	return valDefault, nil
+}
+
+func getDurationOverriddenByEnvOrDefault(s types.String, envKey string, valDefault time.Duration) (time.Duration, error) {
+	val := getStringOverriddenByEnvOrDefault(s, envKey, "")
+	if val == "" {
+		return valDefault, nil
+	}
+
+	return time.ParseDuration(val)
+}
+
 func (p *AdaptiveMetricsProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "grafana-adaptive-metrics"
 	resp.Version = p.version
@@ -110,13 +151,54 @@ func (p *AdaptiveMetricsProvider) Schema(_ context.Context, _ provider.SchemaReq
 				MarkdownDescription: "HTTP headers mapping keys to values used for accessing Grafana Cloud APIs. May alternatively be set via the `GRAFANA_AM_HTTP_HEADERS` environment variable in JSON format.",
 				ElementType:         types.StringType,
 			},
+			"ca_cert": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM encoded CA certificates to trust in addition to the system's, either as a file path or as the literal certificates. Useful behind a TLS-intercepting proxy. May alternatively be set via the `GRAFANA_AM_CA_CERT` environment variable.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM encoded client certificate for mutual TLS, either as a file path or as the literal certificate. Requires `client_key`. May alternatively be set via the `GRAFANA_AM_CLIENT_CERT` environment variable.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "PEM encoded private key of `client_cert`, either as a file path or as the literal key. May alternatively be set via the `GRAFANA_AM_CLIENT_KEY` environment variable.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Skip verification of the API's TLS certificate. Prefer `ca_cert` where possible. Defaults to false. May alternatively be set via the `GRAFANA_AM_INSECURE_SKIP_VERIFY` environment variable.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "URL of the HTTP proxy to send API requests through, such as `http://proxy.example.com:3128`. Defaults to the proxy configured through the `HTTPS_PROXY` and `NO_PROXY` environment variables. May alternatively be set via the `GRAFANA_AM_PROXY_URL` environment variable.",
+			},
 			"retries": schema.Int64Attribute{
 				Optional:            true,
 				MarkdownDescription: "The amount of retries to use for Grafana API and Grafana Cloud API calls. Defaults to 3. May alternatively be set via the `GRAFANA_AM_RETRIES` environment variable.",
 			},
 			"debug": schema.BoolAttribute{
 				Optional:            true,
-				MarkdownDescription: "Whether to enable debug logging. Defaults to false.",
+				MarkdownDescription: "Whether to include request headers and request and response bodies in the provider's API request logs, which are written at the `DEBUG` level. Credentials are redacted. Defaults to false.",
+			},
+			"debug_body_max_bytes": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The number of bytes of each request and response body logged when `debug` is enabled. Longer bodies are truncated. Defaults to 4096. May alternatively be set via the `GRAFANA_AM_DEBUG_BODY_MAX_BYTES` environment variable.",
+			},
+			"rule_batch_window": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "When set to a duration such as `500ms`, creates, updates and deletes of `grafana-adaptive-metrics_rule` resources in the same segment made within this window of each other are coalesced into a single ruleset update. Creates of rules whose `match_type` isn't exact aren't coalesced, as their position in the ruleset decides which rule applies to a metric. Disabled by default. May alternatively be set via the `GRAFANA_AM_RULE_BATCH_WINDOW` environment variable.",
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The maximum number of requests per second sent to the Adaptive Metrics API, useful to stay under the API's rate limits when managing many resources. Unlimited by default. May alternatively be set via the `GRAFANA_AM_MAX_REQUESTS_PER_SECOND` environment variable.",
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The minimum time to wait between retries, as a duration such as `500ms`. Defaults to `1s`. May alternatively be set via the `GRAFANA_AM_RETRY_WAIT_MIN` environment variable.",
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The maximum time to wait between retries, as a duration such as `1m`. A `Retry-After` header sent with a 429 or 503 response is honored up to this limit. Defaults to `30s`. May alternatively be set via the `GRAFANA_AM_RETRY_WAIT_MAX` environment variable.",
 			},
 		},
 	}
@@ -141,15 +223,79 @@ func (p *AdaptiveMetricsProvider) Configure(ctx context.Context, req provider.Co
 		resp.Diagnostics.AddError("Failed to parse GRAFANA_AM_DEBUG", err.Error())
 		return
 	}
+	debugBodyMaxBytes, err := getIntOverriddenByEnvOrDefault(cfg.DebugBodyMaxBytes, "GRAFANA_AM_DEBUG_BODY_MAX_BYTES", client.DefaultDebugBodyMaxBytes)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse GRAFANA_AM_DEBUG_BODY_MAX_BYTES", err.Error())
+		return
+	}
 	retries, err := getIntOverriddenByEnvOrDefault(cfg.Retries, "GRAFANA_AM_RETRIES", 3)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to parse GRAFANA_AM_RETRIES", err.Error())
 		return
 	}
-	httpClient := cleanhttp.DefaultClient()
+	ruleBatchWindow, err := getDurationOverriddenByEnvOrDefault(cfg.RuleBatchWindow, "GRAFANA_AM_RULE_BATCH_WINDOW", 0)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse rule_batch_window", err.Error())
+		return
+	}
+	maxRequestsPerSecond, err := getFloatOverriddenByEnvOrDefault(cfg.MaxRequestsPerSecond, "GRAFANA_AM_MAX_REQUESTS_PER_SECOND", 0)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse max_requests_per_second", err.Error())
+		return
+	}
+	if maxRequestsPerSecond < 0 {
+		resp.Diagnostics.AddError("Invalid max_requests_per_second", "max_requests_per_second must not be negative.")
+		return
+	}
+	retryWaitMin, err := getDurationOverriddenByEnvOrDefault(cfg.RetryWaitMin, "GRAFANA_AM_RETRY_WAIT_MIN", time.Second)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse retry_wait_min", err.Error())
+		return
+	}
+	retryWaitMax, err := getDurationOverriddenByEnvOrDefault(cfg.RetryWaitMax, "GRAFANA_AM_RETRY_WAIT_MAX", 30*time.Second)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse retry_wait_max", err.Error())
+		return
+	}
+	if retryWaitMin > retryWaitMax {
+		resp.Diagnostics.AddError("Invalid retry wait times", fmt.Sprintf("retry_wait_min (%s) must not be greater than retry_wait_max (%s).", retryWaitMin, retryWaitMax))
+		return
+	}
+	insecureSkipVerify, err := getBooleanOverriddenByEnvOrDefault(cfg.InsecureSkipVerify, "GRAFANA_AM_INSECURE_SKIP_VERIFY", false)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse GRAFANA_AM_INSECURE_SKIP_VERIFY", err.Error())
+		return
+	}
+	transport := p.transport
+	if transport == nil {
+		transport, err = newTransport(transportConfig{
+			CACert:             getStringOverriddenByEnvOrDefault(cfg.CACert, "GRAFANA_AM_CA_CERT", ""),
+			ClientCert:         getStringOverriddenByEnvOrDefault(cfg.ClientCert, "GRAFANA_AM_CLIENT_CERT", ""),
+			ClientKey:          getStringOverriddenByEnvOrDefault(cfg.ClientKey, "GRAFANA_AM_CLIENT_KEY", ""),
+			InsecureSkipVerify: insecureSkipVerify,
+			ProxyURL:           getStringOverriddenByEnvOrDefault(cfg.ProxyURL, "GRAFANA_AM_PROXY_URL", ""),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid TLS or proxy configuration", err.Error())
+			return
+		}
+	}
+	// Rate limit below the retrying client, so that retries are limited too.
+	transport = client.RateLimitTransport(transport, maxRequestsPerSecond)
+	httpClient := &http.Client{Transport: transport}
 	if retries > 0 {
 		retryClient := retryablehttp.NewClient()
+		retryClient.HTTPClient = httpClient
+		retryClient.Logger = nil
+		retryClient.RequestLogHook = logRetry
 		retryClient.RetryMax = retries
+		retryClient.RetryWaitMin = retryWaitMin
+		retryClient.RetryWaitMax = retryWaitMax
+		retryClient.Backoff = client.RetryBackoff
+		retryClient.CheckRetry = client.CheckRetry
+		// Hand the last response back to the client once retries are
+		// exhausted, so that it still surfaces a typed API error.
+		retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 		httpClient = retryClient.StandardClient()
 	}
 
@@ -173,9 +319,12 @@ func (p *AdaptiveMetricsProvider) Configure(ctx context.Context, req provider.Co
 	c, err := client.New(apiURL, &client.Config{
 		APIKey:      apiKey,
 		HTTPHeaders: httpHeaders,
+		Logger:      newClientLogger(),
 		Debug:       debug,
 		HttpClient:  httpClient,
 		UserAgent:   fmt.Sprintf("Terraform/%s grafana-adaptive-metrics-provider/%s (commit:%s)", req.TerraformVersion, p.version, p.commit),
+
+		DebugBodyMaxBytes: debugBodyMaxBytes,
 	})
 	if err != nil {
 		resp.Diagnostics.AddError("Could not instantiate the API client.", err.Error())
@@ -183,10 +332,7 @@ func (p *AdaptiveMetricsProvider) Configure(ctx context.Context, req provider.Co
 	}
 
 	aggRules := NewAggregationRules(c)
-	if err = aggRules.Init(); err != nil {
-		resp.Diagnostics.AddError("Could not initialize internal state.", err.Error())
-		return
-	}
+	aggRules.SetBatchWindow(ruleBatchWindow)
 
 	resp.DataSourceData = c // TODO
 	resp.ResourceData = &resourceData{
@@ -200,6 +346,7 @@ func (p *AdaptiveMetricsProvider) Resources(_ context.Context) []func() resource
 		newRuleSetResource,
 		newRuleResource,
 		newExemptionResource,
+		newExemptionSetResource,
 		newRecommendationsConfigResource,
 		newSegmentResource,
 	}
@@ -208,6 +355,11 @@ func (p *AdaptiveMetricsProvider) Resources(_ context.Context) []func() resource
 func (p *AdaptiveMetricsProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		newRecommendationDatasource,
+		newSegmentsDatasource,
+		newSegmentDatasource,
+		newRuleSetDatasource,
+		newRuleDatasource,
+		newExemptionsDatasource,
 	}
 }
 
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"net/http"
+
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
@@ -10,8 +12,14 @@ const (
 	providerConfig = `provider "grafana-adaptive-metrics" {}`
 )
 
-var (
-	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-		"grafana-adaptive-metrics": providerserver.NewProtocol6WithError(New("test", "unknown")()),
+func testAccProtoV6ProviderFactories(transport http.RoundTripper) map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"grafana-adaptive-metrics": func() (tfprotov6.ProviderServer, error) {
+			return providerserver.NewProtocol6WithError(&AdaptiveMetricsProvider{
+				version:   "test",
+				commit:    "unknown",
+				transport: transport,
+			})()
+		},
 	}
-)
+}
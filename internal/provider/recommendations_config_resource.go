@@ -11,8 +11,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 type recommendationsConfigResource struct {
@@ -70,9 +70,9 @@ func (r *recommendationsConfigResource) Create(ctx context.Context, req resource
 		return
 	}
 
-	err := r.client.UpdateAggregationRecommendationsConfig(plan.ToAPIReq())
+	err := r.client.UpdateAggregationRecommendationsConfig(ctx, plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update recommendations config", err.Error())
+		resp.Diagnostics.AddError("Unable to update recommendations config", errorDetail(err))
 	}
 
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
@@ -85,9 +85,9 @@ func (r *recommendationsConfigResource) Create(ctx context.Context, req resource
 }
 
 func (r *recommendationsConfigResource) Read(ctx context.Context, _ resource.ReadRequest, resp *resource.ReadResponse) {
-	cfg, err := r.client.AggregationRecommendationsConfig()
+	cfg, err := r.client.AggregationRecommendationsConfig(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to read recommendations config", err.Error())
+		resp.Diagnostics.AddError("Unable to read recommendations config", errorDetail(err))
 		return
 	}
 
@@ -102,9 +102,9 @@ func (r *recommendationsConfigResource) Update(ctx context.Context, req resource
 		return
 	}
 
-	err := r.client.UpdateAggregationRecommendationsConfig(plan.ToAPIReq())
+	err := r.client.UpdateAggregationRecommendationsConfig(ctx, plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update recommendations config", err.Error())
+		resp.Diagnostics.AddError("Unable to update recommendations config", errorDetail(err))
 	}
 
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
@@ -7,10 +7,10 @@ import (
 )
 
 func TestAccRecommendationsConfigResource(t *testing.T) {
-	CheckAccTestsEnabled(t)
+	acc := CheckAccTestsEnabled(t)
 
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
 		Steps: []resource.TestStep{
 			// Create + Read.
 			{
@@ -8,8 +8,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 type recommendationDatasource struct {
@@ -105,6 +105,11 @@ func (r *recommendationDatasource) Schema(_ context.Context, _ datasource.Schema
 							Description: "The delay until aggregation is performed.",
 						},
 
+						"ingest": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Set to true to keep ingesting the unaggregated series alongside the aggregated ones.",
+						},
+
 						"recommended_action": schema.StringAttribute{
 							Computed:    true,
 							Description: "The recommended action for the aggregation rule.",
@@ -151,9 +156,9 @@ func (r *recommendationDatasource) Read(ctx context.Context, req datasource.Read
 	var state model.AggregationRecommendationListTF
 	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
 
-	recs, err := r.client.AggregationRecommendations(state.Segment.ValueString(), state.IsVerbose(), state.GetActionIn())
+	recs, err := r.client.AggregationRecommendations(ctx, state.Segment.ValueString(), state.IsVerbose(), state.GetActionIn())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to read aggregation rule", err.Error())
+		resp.Diagnostics.AddError("Unable to read aggregation rule", errorDetail(err))
 		return
 	}
 
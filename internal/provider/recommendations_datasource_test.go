@@ -11,14 +11,14 @@ import (
 )
 
 func TestAccRecommendationDatasource(t *testing.T) {
-	CheckAccTestsEnabled(t)
+	acc := CheckAccTestsEnabled(t)
 
 	testAttr := func(attr, value string) resource.TestCheckFunc {
 		return checkMetricRecommendationAttr("data.grafana-adaptive-metrics_recommendations.test", "am_terraform_provider_acceptance_test_metric", attr, value)
 	}
 
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
 		Steps: []resource.TestStep{
 			// Read non-verbose.
 			{
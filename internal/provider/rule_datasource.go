@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type ruleDatasource struct {
+	client *client.Client
+}
+
+var (
+	_ datasource.DataSource              = &ruleDatasource{}
+	_ datasource.DataSourceWithConfigure = &ruleDatasource{}
+)
+
+func newRuleDatasource() datasource.DataSource {
+	return &ruleDatasource{}
+}
+
+func (d *ruleDatasource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected datasource configure type",
+			fmt.Sprintf("Got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data
+}
+
+func (d *ruleDatasource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_rule", req.ProviderTypeName)
+}
+
+func (d *ruleDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := ruleDatasourceAttributes()
+	attributes["metric"] = schema.StringAttribute{
+		Required:    true,
+		Description: "The name of the metric the rule aggregates.",
+	}
+	attributes["segment"] = schema.StringAttribute{
+		Optional:    true,
+		Description: "The name of the segment to read the rule from.",
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads the aggregation rule for a metric, whether or not it is managed by this Terraform configuration.",
+		Attributes:  attributes,
+	}
+}
+
+// ruleDatasourceAttributes returns the read-only attributes of an aggregation
+// rule, as returned by the API.
+func ruleDatasourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"metric": schema.StringAttribute{
+			Computed:    true,
+			Description: "The name of the metric to be aggregated.",
+		},
+		"match_type": schema.StringAttribute{
+			Computed:    true,
+			Description: "Specifies how the metric field matches to incoming metric names. Can be 'prefix', 'suffix', or 'exact', defaults to 'exact'.",
+		},
+
+		"drop": schema.BoolAttribute{
+			Computed:    true,
+			Description: "Set to true to skip both ingestion and aggregation and drop the metric entirely.",
+		},
+		"keep_labels": schema.ListAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+			Description: "The array of labels to keep; labels not in this array will be aggregated.",
+		},
+		"drop_labels": schema.ListAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+			Description: "The array of labels that will be aggregated.",
+		},
+
+		"aggregations": schema.ListAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+			Description: "The array of aggregation types to calculate for this metric.",
+		},
+
+		"aggregation_interval": schema.StringAttribute{
+			Computed:    true,
+			Description: "The interval at which to generate the aggregated series.",
+		},
+		"aggregation_delay": schema.StringAttribute{
+			Computed:    true,
+			Description: "The delay until aggregation is performed.",
+		},
+
+		"ingest": schema.BoolAttribute{
+			Computed:    true,
+			Description: "Set to true to keep ingesting the unaggregated series alongside the aggregated ones.",
+		},
+
+		"managed_by": schema.StringAttribute{
+			Computed:    true,
+			Description: "Who manages the rule, e.g. 'terraform' for rules written by this provider. Empty for rules created without saying who manages them.",
+		},
+	}
+}
+
+func (d *ruleDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config model.RuleDataTF
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, _, err := d.client.ReadAggregationRule(ctx, config.Segment.ValueString(), config.Metric.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read aggregation rule", errorDetail(err))
+		return
+	}
+
+	state := rule.ToRuleDataTF(config.Segment)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
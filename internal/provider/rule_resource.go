@@ -13,8 +13,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 type ruleResource struct {
@@ -54,7 +54,8 @@ func (r *ruleResource) Metadata(_ context.Context, req resource.MetadataRequest,
 
 func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	ruleSchemaCopy := schema.Schema{
-		Attributes: ruleAttributes(true),
+		Description: "Manages a single aggregation rule. The API addresses single rules by metric, so rules of a segment that share a metric but not a match_type can only be managed with the grafana-adaptive-metrics_ruleset resource.",
+		Attributes:  ruleAttributes(true),
 	}
 	// These fields are not part of the shared schema, but are used by the provider to manage the resource.
 	ruleSchemaCopy.Attributes["auto_import"] = schema.BoolAttribute{
@@ -81,28 +82,28 @@ func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 
 	if plan.AutoImport.ValueBool() {
-		_, err := r.rules.Read(plan.Segment.ValueString(), plan.Metric.ValueString())
+		_, err := r.rules.Read(ctx, plan.Segment.ValueString(), plan.Metric.ValueString())
 		if err != nil {
 			// There is no existing rule for this metric; create it.
-			err := r.rules.Create(plan.Segment.ValueString(), plan.ToAPIReq())
+			err := r.rules.Create(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 			if err != nil {
-				resp.Diagnostics.AddError("Unable to create aggregation rule", err.Error())
+				resp.Diagnostics.AddError("Unable to create aggregation rule", errorDetail(err))
 				return
 			}
 		} else {
 			// There is an existing rule for this metric; update it.
-			err := r.rules.Update(plan.Segment.ValueString(), plan.ToAPIReq())
+			err := r.rules.Update(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 			if err != nil {
-				resp.Diagnostics.AddError("Unable to update aggregation rule", err.Error())
+				resp.Diagnostics.AddError("Unable to update aggregation rule", errorDetail(err))
 				return
 			}
 
 			resp.Diagnostics.AddWarning("Existing aggregation rule for metric found", "The existing rule has been updated and imported into Terraform state; no aggregation rule has been created.")
 		}
 	} else {
-		err := r.rules.Create(plan.Segment.ValueString(), plan.ToAPIReq())
+		err := r.rules.Create(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 		if err != nil {
-			resp.Diagnostics.AddError("Unable to create aggregation rule", err.Error())
+			resp.Diagnostics.AddError("Unable to create aggregation rule", errorDetail(err))
 			return
 		}
 	}
@@ -118,17 +119,21 @@ func (r *ruleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	rule, err := r.rules.Read(state.Segment.ValueString(), state.Metric.ValueString())
+	rule, err := r.rules.Read(ctx, state.Segment.ValueString(), state.Metric.ValueString())
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			resp.Diagnostics.AddWarning("Aggregation rule not found", err.Error())
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Unable to read aggregation rule", err.Error())
+		resp.Diagnostics.AddError("Unable to read aggregation rule", errorDetail(err))
 		return
 	}
 
+	if detail, ok := unmodeledFieldsDetail(rule); ok {
+		resp.Diagnostics.AddWarning("Aggregation rule has unsupported fields", detail)
+	}
+
 	tf := rule.ToTF()
 
 	// Segment tells us where to put the rule later, but isn't actually a part
@@ -155,9 +160,9 @@ func (r *ruleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	err := r.rules.Update(plan.Segment.ValueString(), plan.ToAPIReq())
+	err := r.rules.Update(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update aggregation rule", err.Error())
+		resp.Diagnostics.AddError("Unable to update aggregation rule", errorDetail(err))
 		return
 	}
 
@@ -172,12 +177,12 @@ func (r *ruleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	err := r.rules.Delete(state.Segment.ValueString(), state.ToAPIReq())
+	err := r.rules.Delete(ctx, state.Segment.ValueString(), state.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete aggregation rule", err.Error())
+		resp.Diagnostics.AddError("Unable to delete aggregation rule", errorDetail(err))
 	}
 }
 
 func (r *ruleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("metric"), req, resp)
+	importSegmentedID(ctx, path.Root("metric"), req, resp)
 }
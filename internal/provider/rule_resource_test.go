@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -8,26 +9,26 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/stretchr/testify/require"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 func TestAccRuleResource(t *testing.T) {
-	CheckAccTestsEnabled(t)
+	acc := CheckAccTestsEnabled(t)
 
-	metricName := fmt.Sprintf("test_tf_metric_%s", RandString(6))
+	metricName := fmt.Sprintf("test_tf_metric_%s", acc.RandString(6))
 	t.Cleanup(func() {
-		aggRules := AggregationRulesForAccTest(t)
-		_ = aggRules.Delete("", model.AggregationRule{Metric: metricName})
+		aggRules := acc.AggregationRules()
+		_ = aggRules.Delete(context.Background(), "", model.AggregationRule{Metric: metricName})
 	})
 
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
 		Steps: []resource.TestStep{
 			// Create + Read an existing rule w/ auto_import=false (results in an error).
 			{
 				PreConfig: func() {
-					aggRules := AggregationRulesForAccTest(t)
-					require.NoError(t, aggRules.Create("", model.AggregationRule{Metric: metricName, DropLabels: []string{"foobar"}, Aggregations: []string{"sum"}}))
+					aggRules := acc.AggregationRules()
+					require.NoError(t, aggRules.Create(context.Background(), "", model.AggregationRule{Metric: metricName, DropLabels: []string{"foobar"}, Aggregations: []string{"sum"}}))
 				},
 				Config: providerConfig + fmt.Sprintf(`
 resource "grafana-adaptive-metrics_rule" "test" {
@@ -61,8 +62,8 @@ resource "grafana-adaptive-metrics_rule" "test" {
 			// Create + Read, no existing rule.
 			{
 				PreConfig: func() {
-					aggRules := AggregationRulesForAccTest(t)
-					require.NoError(t, aggRules.Delete("", model.AggregationRule{Metric: metricName}))
+					aggRules := acc.AggregationRules()
+					require.NoError(t, aggRules.Delete(context.Background(), "", model.AggregationRule{Metric: metricName}))
 				},
 				Config: providerConfig + fmt.Sprintf(`
 resource "grafana-adaptive-metrics_rule" "test" {
@@ -93,6 +94,15 @@ resource "grafana-adaptive-metrics_rule" "test" {
 				// import.
 				ImportStateVerifyIgnore: []string{"last_updated", "auto_import"},
 			},
+			// ImportState with the default segment spelled out.
+			{
+				ResourceName:                         "grafana-adaptive-metrics_rule.test",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateId:                        "default/" + metricName,
+				ImportStateVerifyIdentifierAttribute: "metric",
+				ImportStateVerifyIgnore:              []string{"last_updated", "auto_import"},
+			},
 			// Update + Read.
 			{
 				Config: providerConfig + fmt.Sprintf(`
@@ -119,8 +129,8 @@ resource "grafana-adaptive-metrics_rule" "test" {
 			// External delete of resource, TF should recreate it.
 			{
 				PreConfig: func() {
-					aggRules := AggregationRulesForAccTest(t)
-					require.NoError(t, aggRules.Delete("", model.AggregationRule{Metric: metricName}))
+					aggRules := acc.AggregationRules()
+					require.NoError(t, aggRules.Delete(context.Background(), "", model.AggregationRule{Metric: metricName}))
 				},
 				Config: providerConfig + fmt.Sprintf(`
 resource "grafana-adaptive-metrics_rule" "test" {
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -23,6 +24,7 @@ func ruleAttributes(replaceOnChange bool) map[string]schema.Attribute {
 		"metric": schema.StringAttribute{
 			Required:      true,
 			Description:   "The name of the metric to be aggregated.",
+			Validators:    []validator.String{nonEmptyString()},
 			PlanModifiers: modifiers,
 		},
 		"match_type": schema.StringAttribute{
@@ -30,6 +32,7 @@ func ruleAttributes(replaceOnChange bool) map[string]schema.Attribute {
 			Computed:    true,
 			Default:     stringdefault.StaticString(""),
 			Description: "Specifies how the metric field matches to incoming metric names. Can be 'prefix', 'suffix', or 'exact', defaults to 'exact'.",
+			Validators:  []validator.String{stringOneOf(matchTypes...)},
 		},
 
 		"drop": schema.BoolAttribute{
@@ -44,6 +47,7 @@ func ruleAttributes(replaceOnChange bool) map[string]schema.Attribute {
 			Computed:    true,
 			Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
 			Description: "The array of labels to keep; labels not in this array will be aggregated.",
+			Validators:  []validator.List{listConflictsWith("drop_labels")},
 		},
 		"drop_labels": schema.ListAttribute{
 			ElementType: types.StringType,
@@ -59,6 +63,7 @@ func ruleAttributes(replaceOnChange bool) map[string]schema.Attribute {
 			Computed:    true,
 			Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
 			Description: "The array of aggregation types to calculate for this metric.",
+			Validators:  []validator.List{listElementsOneOf(aggregationTypes...)},
 		},
 
 		"aggregation_interval": schema.StringAttribute{
@@ -66,12 +71,21 @@ func ruleAttributes(replaceOnChange bool) map[string]schema.Attribute {
 			Computed:    true,
 			Default:     stringdefault.StaticString(""),
 			Description: "The interval at which to generate the aggregated series.",
+			Validators:  []validator.String{promDuration()},
 		},
 		"aggregation_delay": schema.StringAttribute{
 			Optional:    true,
 			Computed:    true,
 			Default:     stringdefault.StaticString(""),
 			Description: "The delay until aggregation is performed.",
+			Validators:  []validator.String{promDuration()},
+		},
+
+		"ingest": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Set to true to keep ingesting the unaggregated series alongside the aggregated ones.",
 		},
 	}
 }
@@ -1,114 +1,729 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
+// maxConflictRetries bounds how many times a write is retried after the API
+// rejected the segment's cached ETag as stale.
+const maxConflictRetries = 3
+
 type AggregationRules struct {
 	client *client.Client
-	mu     sync.RWMutex
 
-	segmentEtags map[string]string
+	// batchWindow is how long rule writes are held back so that they can be
+	// coalesced with other writes to the same segment into a single ruleset
+	// update. Zero disables batching.
+	batchWindow time.Duration
+
+	// mu guards the segments map. Each segment's state is guarded by its own
+	// lock so that operations on independent segments can run concurrently.
+	mu       sync.Mutex
+	segments map[string]*segmentRules
+}
+
+// segmentRules is the cached state of a single segment's rules. It is
+// populated lazily, the first time the segment is read or written.
+type segmentRules struct {
+	// mu serializes writes to the segment, which depend on and replace its
+	// ETag. Reads only hold it for reading so that they can run concurrently
+	// with each other.
+	mu sync.RWMutex
+
+	// cacheMu guards etag and knownRules, which reads update while holding mu
+	// for reading only.
+	cacheMu sync.Mutex
+	// etag is empty until the segment has been read or written.
+	etag string
+
+	// knownRules holds the last version of every rule read or written by this
+	// provider, keyed by metric and match type. It is used to tell a stale ETag apart from a
+	// conflicting change to the rule we're about to write.
+	knownRules map[model.RuleKey]model.AggregationRule
+	// knownAll is set once knownRules holds every rule of the segment, which
+	// is only the case after the segment's rules were read as a whole. It is
+	// cleared, along with knownRules, when a read shows that the rules were
+	// changed elsewhere.
+	knownAll bool
+
+	// batchMu guards pending, the rule writes waiting to be flushed when
+	// batching is enabled.
+	batchMu sync.Mutex
+	pending []*pendingWrite
+}
+
+type writeOp int
+
+const (
+	opCreate writeOp = iota
+	opUpdate
+	opDelete
+)
+
+// pendingWrite is a rule write waiting for its segment's batch to be flushed.
+type pendingWrite struct {
+	op   writeOp
+	rule model.AggregationRule
+	done chan error
+}
+
+// ErrRuleConflict is returned when a rule was changed outside of Terraform
+// between the time it was last read and the time Terraform tried to write it.
+type ErrRuleConflict struct {
+	SegmentID string
+	Metric    string
+	Reason    string
+}
+
+func (e ErrRuleConflict) Error() string {
+	segment := e.SegmentID
+	if segment == "" {
+		segment = "default"
+	}
+	return fmt.Sprintf("aggregation rule for metric %q in segment %q %s", e.Metric, segment, e.Reason)
 }
 
 func NewAggregationRules(c *client.Client) *AggregationRules {
-	return &AggregationRules{client: c, mu: sync.RWMutex{}}
+	return &AggregationRules{client: c, segments: make(map[string]*segmentRules)}
 }
 
-func (r *AggregationRules) Init() error {
+// SetBatchWindow enables coalescing of rule writes made within window of each
+// other into a single ruleset update per segment. A zero window disables it.
+func (r *AggregationRules) SetBatchWindow(window time.Duration) {
+	r.batchWindow = window
+}
+
+// segment returns the cached state for a segment, creating it if necessary.
+func (r *AggregationRules) segment(segmentID string) *segmentRules {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	ruleSets, err := r.client.SegmentedAggregationRules()
-	if err != nil {
-		return err
+	seg, ok := r.segments[segmentID]
+	if !ok {
+		seg = &segmentRules{knownRules: make(map[model.RuleKey]model.AggregationRule)}
+		r.segments[segmentID] = seg
 	}
+	return seg
+}
 
-	r.segmentEtags = make(map[string]string, len(ruleSets))
-	for _, ruleSet := range ruleSets {
-		r.segmentEtags[ruleSet.Segment.ID] = ruleSet.Etag
+func (r *AggregationRules) Create(ctx context.Context, segmentID string, rule model.AggregationRule) error {
+	seg := r.segment(segmentID)
+	// A batched create is appended to the ruleset, which may not be where
+	// the API would place the rule, and the first prefix or suffix rule that
+	// matches a metric wins. Only exact rules don't depend on their position.
+	if r.batchWindow > 0 && rule.IsExactMatch() {
+		return r.enqueue(ctx, segmentID, seg, opCreate, rule)
 	}
 
-	return nil
-}
+	seg.mu.Lock()
+	defer seg.mu.Unlock()
 
-func (r *AggregationRules) Create(segmentID string, rule model.AggregationRule) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.checkOnlyRuleForMetric(ctx, segmentID, seg, rule); err != nil {
+		return err
+	}
 
-	etag, err := r.client.CreateAggregationRule(segmentID, rule, r.segmentEtags[segmentID])
+	err := r.writeWithConflictRetry(ctx, segmentID, seg, rule.Key(), &rule,
+		func(etag string) (string, error) {
+			return r.client.CreateAggregationRule(ctx, segmentID, rule, etag)
+		},
+		func(current *model.AggregationRule) (bool, error) {
+			if current != nil {
+				return false, ErrRuleConflict{SegmentID: segmentID, Metric: rule.Metric, Reason: "was created outside of Terraform"}
+			}
+			return false, nil
+		},
+	)
 	if err != nil {
 		return err
 	}
 
-	r.segmentEtags[segmentID] = etag
+	seg.setKnownRule(rule)
 	return nil
 }
 
-func (r *AggregationRules) Read(segmentID string, metric string) (model.AggregationRule, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *AggregationRules) Read(ctx context.Context, segmentID string, metric string) (model.AggregationRule, error) {
+	seg := r.segment(segmentID)
+	seg.mu.RLock()
+	defer seg.mu.RUnlock()
 
-	rule, etag, err := r.client.ReadAggregationRule(segmentID, metric)
+	rule, etag, err := r.client.ReadAggregationRule(ctx, segmentID, metric)
 	if err != nil {
 		return model.AggregationRule{}, err
 	}
 
-	r.segmentEtags[segmentID] = etag
+	seg.observeEtag(etag)
+	seg.setKnownRule(rule)
 	return rule, nil
 }
 
-func (r *AggregationRules) Update(segmentID string, rule model.AggregationRule) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *AggregationRules) Update(ctx context.Context, segmentID string, rule model.AggregationRule) error {
+	seg := r.segment(segmentID)
+	if r.batchWindow > 0 {
+		return r.enqueue(ctx, segmentID, seg, opUpdate, rule)
+	}
 
-	etag, err := r.client.UpdateAggregationRule(segmentID, rule, r.segmentEtags[segmentID])
+	seg.mu.Lock()
+	defer seg.mu.Unlock()
+
+	// The update replaces the whole rule, so the fields Terraform can't
+	// express must be merged in from the rule's current version, which
+	// checkOnlyRuleForMetric reads if needed.
+	if err := r.checkOnlyRuleForMetric(ctx, segmentID, seg, rule); err != nil {
+		return err
+	}
+	want := seg.withUnmodeledFields(rule)
+
+	err := r.writeWithConflictRetry(ctx, segmentID, seg, rule.Key(), &want,
+		func(etag string) (string, error) {
+			return r.client.UpdateAggregationRule(ctx, segmentID, want, etag)
+		},
+		func(current *model.AggregationRule) (bool, error) {
+			if current == nil {
+				return false, ErrRuleConflict{SegmentID: segmentID, Metric: rule.Metric, Reason: "was deleted outside of Terraform"}
+			}
+			if err := seg.checkUnchanged(segmentID, *current); err != nil {
+				return false, err
+			}
+			// Fields Terraform can't express may have changed since the
+			// rule was last seen; keep the ones it has now.
+			want = rule.WithUnmodeledFields(*current)
+			return false, nil
+		},
+	)
 	if err != nil {
 		return err
 	}
 
-	r.segmentEtags[segmentID] = etag
+	seg.setKnownRule(want)
 	return nil
 }
 
-func (r *AggregationRules) Delete(segmentID string, rule model.AggregationRule) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *AggregationRules) Delete(ctx context.Context, segmentID string, rule model.AggregationRule) error {
+	seg := r.segment(segmentID)
+	if r.batchWindow > 0 {
+		return r.enqueue(ctx, segmentID, seg, opDelete, rule)
+	}
+
+	seg.mu.Lock()
+	defer seg.mu.Unlock()
 
-	etag, err := r.client.DeleteAggregationRule(segmentID, rule.Metric, r.segmentEtags[segmentID])
+	if err := r.checkOnlyRuleForMetric(ctx, segmentID, seg, rule); err != nil {
+		return err
+	}
+
+	err := r.writeWithConflictRetry(ctx, segmentID, seg, rule.Key(), nil,
+		func(etag string) (string, error) {
+			return r.client.DeleteAggregationRule(ctx, segmentID, rule.Metric, etag)
+		},
+		func(current *model.AggregationRule) (bool, error) {
+			if current == nil {
+				// Someone else already deleted it, which is what we wanted.
+				return true, nil
+			}
+			return false, seg.checkUnchanged(segmentID, *current)
+		},
+	)
 	if err != nil {
 		return err
 	}
 
-	r.segmentEtags[segmentID] = etag
+	seg.forgetKnownRule(rule.Key())
 	return nil
 }
 
-func (r *AggregationRules) ReadRuleSet(segmentID string) (model.AggregationRuleSet, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *AggregationRules) ReadRuleSet(ctx context.Context, segmentID string) (model.AggregationRuleSet, error) {
+	seg := r.segment(segmentID)
+	seg.mu.RLock()
+	defer seg.mu.RUnlock()
 
-	rules, etag, err := r.client.ReadAggregationRuleSet(segmentID)
+	rules, etag, err := r.client.ReadAggregationRuleSet(ctx, segmentID)
 	if err != nil {
 		return nil, err
 	}
 
-	r.segmentEtags[segmentID] = etag
+	seg.refresh(etag, rules)
 	return rules, nil
 }
 
-func (r *AggregationRules) UpdateRuleSet(segmentID string, rules model.AggregationRuleSet) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *AggregationRules) UpdateRuleSet(ctx context.Context, segmentID string, rules model.AggregationRuleSet) error {
+	seg := r.segment(segmentID)
+	seg.mu.Lock()
+	defer seg.mu.Unlock()
+
+	// ensureKnownRules also fetches the ETag if needed.
+	if err := r.ensureKnownRules(ctx, segmentID, seg); err != nil {
+		return err
+	}
+
+	merged := make(model.AggregationRuleSet, len(rules))
+	for i, rule := range rules {
+		merged[i] = seg.withUnmodeledFields(rule)
+	}
+	rules = merged
+
+	etag, err := r.client.UpdateAggregationRuleSet(ctx, segmentID, rules, seg.getEtag())
+	if err != nil {
+		if client.IsErrAmbiguousWrite(err) {
+			return r.reconcileRuleSet(ctx, segmentID, seg, rules, err)
+		}
+		return err
+	}
+
+	seg.setEtag(etag)
+	seg.setKnownRuleSet(rules)
+	return nil
+}
+
+// writeWithConflictRetry performs a write guarded by the segment's cached ETag.
+// If the API rejects the ETag as stale, the segment's rules are re-read to
+// refresh it and verify is called with the current version of the targeted
+// rule (nil if it doesn't exist). verify decides whether the write is still
+// safe to retry, already done, or conflicts with a change made elsewhere.
+//
+// want is the rule the write is expected to leave behind, or nil for a
+// delete. If the write fails without telling whether it was applied, the
+// rule is read back and compared to want to find out.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) writeWithConflictRetry(
+	ctx context.Context,
+	segmentID string,
+	seg *segmentRules,
+	key model.RuleKey,
+	want *model.AggregationRule,
+	write func(etag string) (string, error),
+	verify func(current *model.AggregationRule) (done bool, err error),
+) error {
+	if err := r.ensureEtag(ctx, segmentID, seg); err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		etag, err := write(seg.getEtag())
+		if err == nil {
+			seg.setEtag(etag)
+			return nil
+		}
+
+		if client.IsErrAmbiguousWrite(err) {
+			return r.reconcileRule(ctx, segmentID, seg, key, want, err)
+		}
+
+		if !client.IsErrPreconditionFailed(err) || attempt >= maxConflictRetries {
+			return err
+		}
+
+		rules, etag, readErr := r.client.ReadAggregationRuleSet(ctx, segmentID)
+		if readErr != nil {
+			return errors.Join(err, fmt.Errorf("could not refresh rules after stale ETag: %w", readErr))
+		}
+
+		// The rule is checked against the version we last saw before the
+		// cache is refreshed with the segment's current rules.
+		done, err := verify(findRule(rules, key))
+		seg.refresh(etag, rules)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// reconcileRule finds out whether a rule write that failed with the ambiguous
+// writeErr was applied, by reading the segment's rules back. It returns nil if
+// the rule is in the state the write meant to leave it in, and writeErr
+// otherwise. Either way the segment's ETag and rules are refreshed, so that
+// the next write doesn't fail on a stale ETag.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) reconcileRule(ctx context.Context, segmentID string, seg *segmentRules, key model.RuleKey, want *model.AggregationRule, writeErr error) error {
+	rules, etag, err := r.client.ReadAggregationRuleSet(ctx, segmentID)
+	if err != nil {
+		return errors.Join(writeErr, fmt.Errorf("could not read rules back to check whether the write was applied: %w", err))
+	}
+	seg.refresh(etag, rules)
+
+	current := findRule(rules, key)
+	switch {
+	case want == nil && current == nil:
+		return nil
+	case want != nil && current != nil && want.Equal(*current):
+		return nil
+	default:
+		return fmt.Errorf("%w; reading the rules back showed it was not applied", writeErr)
+	}
+}
+
+// reconcileRuleSet is like reconcileRule, for a write replacing all of the
+// segment's rules with want.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) reconcileRuleSet(ctx context.Context, segmentID string, seg *segmentRules, want model.AggregationRuleSet, writeErr error) error {
+	rules, etag, err := r.client.ReadAggregationRuleSet(ctx, segmentID)
+	if err != nil {
+		return errors.Join(writeErr, fmt.Errorf("could not read rules back to check whether the write was applied: %w", err))
+	}
+	seg.refresh(etag, rules)
+
+	if !slices.EqualFunc(rules, want, model.AggregationRule.Equal) {
+		return fmt.Errorf("%w; reading the rules back showed it was not applied", writeErr)
+	}
+	return nil
+}
+
+// unmodeledFieldsDetail describes the fields set on rules that the provider
+// doesn't model, or returns false if there are none.
+func unmodeledFieldsDetail(rules ...model.AggregationRule) (string, bool) {
+	var lines []string
+	for _, rule := range rules {
+		if fields := rule.UnmodeledFields(); len(fields) > 0 {
+			lines = append(lines, fmt.Sprintf("- %s: %s", rule.Metric, strings.Join(fields, ", ")))
+		}
+	}
+	if len(lines) == 0 {
+		return "", false
+	}
+	return "The following aggregation rules have fields this version of the provider does not support. " +
+		"They are kept as they are when the rules are updated, but cannot be managed with Terraform; " +
+		"consider upgrading the provider.\n\n" + strings.Join(lines, "\n"), true
+}
+
+// findRule returns the rule with key, or nil if rules has none.
+func findRule(rules []model.AggregationRule, key model.RuleKey) *model.AggregationRule {
+	for i := range rules {
+		if rules[i].Key() == key {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// checkOnlyRuleForMetric returns an ErrRuleConflict if the segment has a rule
+// for the metric of rule with another match type. The API addresses single
+// rules by metric alone, so a write to one of them could hit the other.
+// Rulesets are written whole and don't have this limitation.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) checkOnlyRuleForMetric(ctx context.Context, segmentID string, seg *segmentRules, rule model.AggregationRule) error {
+	if err := r.ensureKnownRules(ctx, segmentID, seg); err != nil {
+		return err
+	}
+
+	seg.cacheMu.Lock()
+	defer seg.cacheMu.Unlock()
+
+	for key := range seg.knownRules {
+		if key.Metric == rule.Metric && key != rule.Key() {
+			return ErrRuleConflict{
+				SegmentID: segmentID,
+				Metric:    rule.Metric,
+				Reason: fmt.Sprintf("shares its metric with a rule whose match_type is %q, and rules sharing a metric can't be managed one by one; "+
+					"manage them with a grafana-adaptive-metrics_ruleset resource instead", key.MatchType),
+			}
+		}
+	}
+	return nil
+}
 
-	etag, err := r.client.UpdateAggregationRuleSet(segmentID, rules, r.segmentEtags[segmentID])
+// ensureEtag fetches the segment's ETag the first time the segment is written
+// to, unless an earlier read already returned it.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) ensureEtag(ctx context.Context, segmentID string, seg *segmentRules) error {
+	if seg.getEtag() != "" {
+		return nil
+	}
+
+	rules, etag, err := r.client.ReadAggregationRuleSet(ctx, segmentID)
 	if err != nil {
+		return fmt.Errorf("could not read the segment's current rules: %w", err)
+	}
+
+	seg.refresh(etag, rules)
+	return nil
+}
+
+// ensureKnownRules reads the segment's rules unless knownRules already holds
+// all of them, so that fields Terraform can't express can be carried over
+// from rules it hasn't read itself. Rules that were already known keep the
+// version conflicts are checked against, and so does the ETag.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) ensureKnownRules(ctx context.Context, segmentID string, seg *segmentRules) error {
+	if seg.knowsAllRules() {
+		return nil
+	}
+
+	rules, etag, err := r.client.ReadAggregationRuleSet(ctx, segmentID)
+	if err != nil {
+		return fmt.Errorf("could not read the segment's current rules: %w", err)
+	}
+
+	if seg.getEtag() == "" {
+		seg.setEtag(etag)
+	}
+	seg.learnRules(rules)
+	return nil
+}
+
+// enqueue adds a write to the segment's pending batch and waits for the batch
+// to be flushed. The first write of a batch schedules the flush.
+func (r *AggregationRules) enqueue(ctx context.Context, segmentID string, seg *segmentRules, op writeOp, rule model.AggregationRule) error {
+	w := &pendingWrite{op: op, rule: rule, done: make(chan error, 1)}
+
+	seg.batchMu.Lock()
+	seg.pending = append(seg.pending, w)
+	if len(seg.pending) == 1 {
+		// The batch serves several callers, so it must not be cancelled along
+		// with whichever one happened to start it.
+		flushCtx := context.WithoutCancel(ctx)
+		time.AfterFunc(r.batchWindow, func() {
+			r.flush(flushCtx, segmentID, seg)
+		})
+	}
+	seg.batchMu.Unlock()
+
+	select {
+	case err := <-w.done:
 		return err
+	case <-ctx.Done():
 	}
 
-	r.segmentEtags[segmentID] = etag
+	// A write that is still pending is dropped from the batch, so that it
+	// isn't applied after the caller gave up on it. Once the flush has taken
+	// it, its outcome is reported instead, since it may have been applied.
+	seg.batchMu.Lock()
+	idx := slices.Index(seg.pending, w)
+	if idx >= 0 {
+		seg.pending = slices.Delete(seg.pending, idx, idx+1)
+	}
+	seg.batchMu.Unlock()
+
+	if idx >= 0 {
+		return ctx.Err()
+	}
+	return <-w.done
+}
+
+// flush applies all pending writes of a segment to its current rules and
+// saves the result with a single ruleset update. Writes that conflict with
+// changes made outside of Terraform fail individually without failing the
+// rest of the batch.
+func (r *AggregationRules) flush(ctx context.Context, segmentID string, seg *segmentRules) {
+	seg.batchMu.Lock()
+	writes := seg.pending
+	seg.pending = nil
+	seg.batchMu.Unlock()
+
+	// Every write of the batch may have been cancelled.
+	if len(writes) == 0 {
+		return
+	}
+
+	seg.mu.Lock()
+	defer seg.mu.Unlock()
+
+	results := make(map[*pendingWrite]error, len(writes))
+	err := r.applyBatch(ctx, segmentID, seg, writes, results)
+	for _, w := range writes {
+		if writeErr, ok := results[w]; ok {
+			w.done <- writeErr
+		} else {
+			w.done <- err
+		}
+	}
+}
+
+// applyBatch records the outcome of writes that failed or didn't need to be
+// sent in results, and returns the outcome of the ruleset update for all
+// other writes.
+//
+// Must be called with seg.mu held for writing.
+func (r *AggregationRules) applyBatch(ctx context.Context, segmentID string, seg *segmentRules, writes []*pendingWrite, results map[*pendingWrite]error) error {
+	for attempt := 0; ; attempt++ {
+		clear(results)
+
+		// The cache is refreshed with upstream only once the writes were
+		// checked against the versions of the rules we last saw.
+		upstream, readEtag, err := r.client.ReadAggregationRuleSet(ctx, segmentID)
+		if err != nil {
+			return err
+		}
+
+		rules := make(model.AggregationRuleSet, len(upstream))
+		copy(rules, upstream)
+
+		// Rules already written earlier in this batch no longer match what
+		// we've last seen, so they are not checked for conflicts again.
+		touched := make(map[model.RuleKey]bool, len(writes))
+		changed := false
+		for _, w := range writes {
+			key := w.rule.Key()
+			idx := slices.IndexFunc(rules, func(rule model.AggregationRule) bool {
+				return rule.Key() == key
+			})
+
+			switch {
+			case w.op == opCreate && idx >= 0:
+				results[w] = ErrRuleConflict{SegmentID: segmentID, Metric: w.rule.Metric, Reason: "was created outside of Terraform"}
+			case w.op == opCreate:
+				rules = append(rules, w.rule)
+				touched[key] = true
+				changed = true
+			case idx < 0 && w.op == opUpdate:
+				results[w] = ErrRuleConflict{SegmentID: segmentID, Metric: w.rule.Metric, Reason: "was deleted outside of Terraform"}
+			case idx < 0:
+				// Someone else already deleted it, which is what we wanted.
+				results[w] = nil
+			default:
+				if !touched[key] {
+					if err := seg.checkUnchanged(segmentID, rules[idx]); err != nil {
+						results[w] = err
+						continue
+					}
+				}
+				if w.op == opUpdate {
+					rules[idx] = w.rule.WithUnmodeledFields(rules[idx])
+				} else {
+					rules = slices.Delete(rules, idx, idx+1)
+				}
+				touched[key] = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			seg.refresh(readEtag, upstream)
+			return nil
+		}
+
+		etag, err := r.client.UpdateAggregationRuleSet(ctx, segmentID, rules, readEtag)
+		if err != nil {
+			if client.IsErrPreconditionFailed(err) && attempt < maxConflictRetries {
+				continue
+			}
+			if client.IsErrAmbiguousWrite(err) {
+				return r.reconcileRuleSet(ctx, segmentID, seg, rules, err)
+			}
+			seg.refresh(readEtag, upstream)
+			return err
+		}
+
+		seg.setEtag(etag)
+		seg.setKnownRuleSet(rules)
+		return nil
+	}
+}
+
+func (s *segmentRules) getEtag() string {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return s.etag
+}
+
+// setEtag records the ETag returned by a write of ours. The caller records
+// how the write changed the rules.
+func (s *segmentRules) setEtag(etag string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.etag = etag
+}
+
+// observeEtag records the ETag returned by a read of some of the segment's
+// rules. If it changed, the rules were changed elsewhere since we last saw
+// them, and the rules we know of are forgotten.
+func (s *segmentRules) observeEtag(etag string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	if s.etag != "" && s.etag != etag {
+		s.knownRules = make(map[model.RuleKey]model.AggregationRule)
+		s.knownAll = false
+	}
+	s.etag = etag
+}
+
+// refresh records the ETag and rules returned by a read of all of the
+// segment's rules.
+func (s *segmentRules) refresh(etag string, rules []model.AggregationRule) {
+	s.setEtag(etag)
+	s.setKnownRuleSet(rules)
+}
+
+// checkUnchanged returns an ErrRuleConflict if current differs from the last
+// version of the rule this provider has seen. Rules we haven't seen before
+// can't be checked and are assumed unchanged.
+func (s *segmentRules) checkUnchanged(segmentID string, current model.AggregationRule) error {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	known, ok := s.knownRules[current.Key()]
+	if ok && !known.Equal(current) {
+		return ErrRuleConflict{SegmentID: segmentID, Metric: current.Metric, Reason: "was modified outside of Terraform"}
+	}
 	return nil
 }
+
+// withUnmodeledFields carries over the fields Terraform can't express from the
+// last known version of rule, so writing it back doesn't wipe them.
+func (s *segmentRules) withUnmodeledFields(rule model.AggregationRule) model.AggregationRule {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	if known, ok := s.knownRules[rule.Key()]; ok {
+		return rule.WithUnmodeledFields(known)
+	}
+	return rule
+}
+
+func (s *segmentRules) setKnownRule(rule model.AggregationRule) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.knownRules[rule.Key()] = rule
+}
+
+func (s *segmentRules) forgetKnownRule(key model.RuleKey) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	delete(s.knownRules, key)
+}
+
+func (s *segmentRules) setKnownRuleSet(rules []model.AggregationRule) {
+	known := make(map[model.RuleKey]model.AggregationRule, len(rules))
+	for _, rule := range rules {
+		known[rule.Key()] = rule
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.knownRules = known
+	s.knownAll = true
+}
+
+// learnRules adds the rules that aren't known yet to knownRules, and marks
+// knownRules as holding every rule of the segment.
+func (s *segmentRules) learnRules(rules []model.AggregationRule) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	for _, rule := range rules {
+		if _, ok := s.knownRules[rule.Key()]; !ok {
+			s.knownRules[rule.Key()] = rule
+		}
+	}
+	s.knownAll = true
+}
+
+func (s *segmentRules) knowsAllRules() bool {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return s.knownAll
+}
@@ -0,0 +1,782 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+// testRuleServer is a minimal implementation of the default segment's rules
+// endpoints with ETag semantics.
+type testRuleServer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	version  int
+	rules    []model.AggregationRule
+	writes   int
+	requests []string
+
+	// failNextWrites makes the next writes fail with a 500 without being
+	// applied, and loseNextWrites makes them fail with a 500 after being
+	// applied, as if the response had been lost.
+	failNextWrites int
+	loseNextWrites int
+}
+
+func newTestRuleServer(t *testing.T, rules ...model.AggregationRule) *testRuleServer {
+	s := &testRuleServer{t: t, rules: rules, version: 1}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *testRuleServer) etag() string {
+	return fmt.Sprintf("\"%d\"", s.version)
+}
+
+// edit simulates a change made outside of the provider, e.g. in the UI.
+func (s *testRuleServer) edit(f func(rules []model.AggregationRule) []model.AggregationRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = f(s.rules)
+	s.version++
+}
+
+func (s *testRuleServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.URL.Path == "/aggregations/rules" && r.Method == http.MethodGet:
+		w.Header().Set("ETag", s.etag())
+		_ = json.NewEncoder(w).Encode(s.rules)
+		return
+	case r.URL.Path == "/aggregations/rules" && r.Method == http.MethodPost:
+		if r.Header.Get("If-Match") != s.etag() {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if s.failWrite(w) {
+			return
+		}
+		s.rules = nil
+		require.NoError(s.t, json.NewDecoder(r.Body).Decode(&s.rules))
+		s.writes++
+		s.version++
+		if s.loseWrite(w) {
+			return
+		}
+		w.Header().Set("ETag", s.etag())
+		return
+	case !strings.HasPrefix(r.URL.Path, "/aggregations/rule/"):
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	metric := strings.TrimPrefix(r.URL.Path, "/aggregations/rule/")
+	idx := -1
+	for i, rule := range s.rules {
+		if rule.Metric == metric {
+			idx = i
+		}
+	}
+
+	if r.Method != http.MethodGet {
+		if r.Header.Get("If-Match") != s.etag() {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if s.failWrite(w) {
+			return
+		}
+		s.writes++
+	}
+
+	var rule model.AggregationRule
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		require.NoError(s.t, json.NewDecoder(r.Body).Decode(&rule))
+	}
+
+	switch {
+	case r.Method == http.MethodPost && idx < 0:
+		s.rules = append(s.rules, rule)
+	case r.Method == http.MethodPut && idx >= 0:
+		s.rules[idx] = rule
+	case r.Method == http.MethodDelete && idx >= 0:
+		s.rules = append(s.rules[:idx], s.rules[idx+1:]...)
+	case r.Method == http.MethodGet && idx >= 0:
+		w.Header().Set("ETag", s.etag())
+		_ = json.NewEncoder(w).Encode(s.rules[idx])
+		return
+	case idx < 0:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.version++
+	if s.loseWrite(w) {
+		return
+	}
+	w.Header().Set("ETag", s.etag())
+}
+
+func (s *testRuleServer) failWrite(w http.ResponseWriter) bool {
+	if s.failNextWrites == 0 {
+		return false
+	}
+	s.failNextWrites--
+	w.WriteHeader(http.StatusInternalServerError)
+	return true
+}
+
+func (s *testRuleServer) loseWrite(w http.ResponseWriter) bool {
+	if s.loseNextWrites == 0 {
+		return false
+	}
+	s.loseNextWrites--
+	w.WriteHeader(http.StatusInternalServerError)
+	return true
+}
+
+func newTestAggregationRules(t *testing.T, s *testRuleServer) *AggregationRules {
+	c, err := client.New(s.server.URL, &client.Config{})
+	require.NoError(t, err)
+
+	return NewAggregationRules(c)
+}
+
+func TestAggregationRulesFetchesETagsLazily(t *testing.T) {
+	ctx := context.Background()
+	s := newTestRuleServer(t)
+	aggRules := newTestAggregationRules(t, s)
+	require.Empty(t, s.requests)
+
+	require.NoError(t, aggRules.Create(ctx, "", model.AggregationRule{Metric: "a"}))
+	require.NoError(t, aggRules.Create(ctx, "", model.AggregationRule{Metric: "b"}))
+	require.Equal(t, []string{
+		"GET /aggregations/rules",
+		"POST /aggregations/rule/a",
+		"POST /aggregations/rule/b",
+	}, s.requests)
+
+	// A read returns the ETag as well, but a delete still needs all of the
+	// segment's rules to check that no other rule shares the metric. The
+	// ruleset is read only once.
+	s.requests = nil
+	aggRules = newTestAggregationRules(t, s)
+	_, err := aggRules.Read(ctx, "", "a")
+	require.NoError(t, err)
+	require.NoError(t, aggRules.Delete(ctx, "", model.AggregationRule{Metric: "a"}))
+	require.NoError(t, aggRules.Delete(ctx, "", model.AggregationRule{Metric: "b"}))
+	require.Equal(t, []string{
+		"GET /aggregations/rule/a",
+		"GET /aggregations/rules",
+		"DELETE /aggregations/rule/a",
+		"DELETE /aggregations/rule/b",
+	}, s.requests)
+}
+
+func TestAggregationRulesRecoversFromStaleETag(t *testing.T) {
+	ctx := context.Background()
+	s := newTestRuleServer(t, model.AggregationRule{Metric: "unrelated", Drop: true})
+	aggRules := newTestAggregationRules(t, s)
+	_, err := aggRules.ReadRuleSet(ctx, "")
+	require.NoError(t, err)
+
+	// A rule for another metric changes, invalidating the cached ETag.
+	s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+		rules[0].Drop = false
+		return rules
+	})
+
+	require.NoError(t, aggRules.Create(ctx, "", model.AggregationRule{Metric: "created", Drop: true}))
+	require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "created"}))
+	require.Equal(t, 2, s.writes)
+
+	s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+		return append(rules, model.AggregationRule{Metric: "other"})
+	})
+
+	require.NoError(t, aggRules.Delete(ctx, "", model.AggregationRule{Metric: "created"}))
+	require.Equal(t, []model.AggregationRule{{Metric: "unrelated"}, {Metric: "other"}}, s.rules)
+}
+
+func TestAggregationRulesDetectsConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create of a rule created elsewhere", func(t *testing.T) {
+		s := newTestRuleServer(t)
+		aggRules := newTestAggregationRules(t, s)
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+			return append(rules, model.AggregationRule{Metric: "a", Drop: true})
+		})
+
+		err = aggRules.Create(ctx, "", model.AggregationRule{Metric: "a"})
+		var conflict ErrRuleConflict
+		require.ErrorAs(t, err, &conflict)
+		require.Equal(t, "a", conflict.Metric)
+		require.Equal(t, 0, s.writes)
+	})
+
+	t.Run("update of a rule modified elsewhere", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Drop: true})
+		aggRules := newTestAggregationRules(t, s)
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+			rules[0].Aggregations = []string{"sum"}
+			return rules
+		})
+
+		err = aggRules.Update(ctx, "", model.AggregationRule{Metric: "a"})
+		var conflict ErrRuleConflict
+		require.ErrorAs(t, err, &conflict)
+		require.Contains(t, conflict.Error(), "was modified outside of Terraform")
+		require.Equal(t, 0, s.writes)
+	})
+
+	t.Run("update of a rule deleted elsewhere", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Drop: true})
+		aggRules := newTestAggregationRules(t, s)
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+
+		s.edit(func([]model.AggregationRule) []model.AggregationRule {
+			return nil
+		})
+
+		err = aggRules.Update(ctx, "", model.AggregationRule{Metric: "a"})
+		var conflict ErrRuleConflict
+		require.ErrorAs(t, err, &conflict)
+		require.Contains(t, conflict.Error(), "was deleted outside of Terraform")
+	})
+
+	t.Run("delete of a rule deleted elsewhere", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Drop: true})
+		aggRules := newTestAggregationRules(t, s)
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+
+		s.edit(func([]model.AggregationRule) []model.AggregationRule {
+			return nil
+		})
+
+		require.NoError(t, aggRules.Delete(ctx, "", model.AggregationRule{Metric: "a"}))
+		require.Equal(t, 0, s.writes)
+	})
+}
+
+func TestAggregationRulesGivesUpAfterRepeatedConflicts(t *testing.T) {
+	s := newTestRuleServer(t)
+	aggRules := newTestAggregationRules(t, s)
+
+	// Every refresh is immediately outdated again.
+	s.server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule { return rules })
+		s.handle(w, r)
+	})
+
+	err := aggRules.Create(context.Background(), "", model.AggregationRule{Metric: "a"})
+	require.True(t, client.IsErrPreconditionFailed(err))
+}
+
+func TestAggregationRulesReconcilesAmbiguousWrites(t *testing.T) {
+	ctx := context.Background()
+
+	// Use the same retry policy as the provider, so that the test also
+	// covers ambiguous failures not being retried.
+	newRetryingAggregationRules := func(t *testing.T, s *testRuleServer) *AggregationRules {
+		retryClient := retryablehttp.NewClient()
+		retryClient.Logger = nil
+		retryClient.RetryWaitMin = time.Millisecond
+		retryClient.RetryWaitMax = time.Millisecond
+		retryClient.CheckRetry = client.CheckRetry
+		retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+		c, err := client.New(s.server.URL, &client.Config{HttpClient: retryClient.StandardClient()})
+		require.NoError(t, err)
+		return NewAggregationRules(c)
+	}
+
+	t.Run("create whose response was lost", func(t *testing.T) {
+		s := newTestRuleServer(t)
+		aggRules := newRetryingAggregationRules(t, s)
+		s.loseNextWrites = 1
+
+		require.NoError(t, aggRules.Create(ctx, "", model.AggregationRule{Metric: "a", Drop: true}))
+		require.Equal(t, 1, s.writes)
+
+		// The ETag was refreshed while reconciling.
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "a"}))
+		require.Equal(t, []model.AggregationRule{{Metric: "a"}}, s.rules)
+	})
+
+	t.Run("create that failed", func(t *testing.T) {
+		s := newTestRuleServer(t)
+		aggRules := newRetryingAggregationRules(t, s)
+		s.failNextWrites = 1
+
+		err := aggRules.Create(ctx, "", model.AggregationRule{Metric: "a", Drop: true})
+		require.True(t, client.IsErrAmbiguousWrite(err), "unexpected error: %v", err)
+		require.Empty(t, s.rules)
+	})
+
+	t.Run("delete whose response was lost", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a"})
+		aggRules := newRetryingAggregationRules(t, s)
+		s.loseNextWrites = 1
+
+		require.NoError(t, aggRules.Delete(ctx, "", model.AggregationRule{Metric: "a"}))
+		require.Empty(t, s.rules)
+	})
+
+	t.Run("ruleset update whose response was lost", func(t *testing.T) {
+		s := newTestRuleServer(t)
+		aggRules := newRetryingAggregationRules(t, s)
+		s.loseNextWrites = 1
+
+		want := model.AggregationRuleSet{{Metric: "a"}, {Metric: "b", Drop: true}}
+		require.NoError(t, aggRules.UpdateRuleSet(ctx, "", want))
+		require.Equal(t, 1, s.writes)
+		require.NoError(t, aggRules.Create(ctx, "", model.AggregationRule{Metric: "c"}))
+	})
+
+	t.Run("batched writes whose response was lost", func(t *testing.T) {
+		s := newTestRuleServer(t)
+		aggRules := newRetryingAggregationRules(t, s)
+		aggRules.SetBatchWindow(10 * time.Millisecond)
+		s.loseNextWrites = 1
+
+		require.NoError(t, aggRules.Create(ctx, "", model.AggregationRule{Metric: "a"}))
+		require.Equal(t, 1, s.writes)
+	})
+}
+
+func TestAggregationRulesWritesToDifferentSegmentsRunConcurrently(t *testing.T) {
+	bothArrived := make(chan struct{})
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	go func() {
+		arrived.Wait()
+		close(bothArrived)
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", "\"etag\"")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+
+		// Each write blocks until the other segment's write has arrived too,
+		// which can only happen if they aren't serialized.
+		arrived.Done()
+		select {
+		case <-bothArrived:
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL, &client.Config{})
+	require.NoError(t, err)
+	aggRules := NewAggregationRules(c)
+
+	errs := make(chan error, 2)
+	for _, segmentID := range []string{"segment-a", "segment-b"} {
+		go func(segmentID string) {
+			errs <- aggRules.Create(context.Background(), segmentID, model.AggregationRule{Metric: "a"})
+		}(segmentID)
+	}
+
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+}
+
+func TestAggregationRulesBatchesWrites(t *testing.T) {
+	ctx := context.Background()
+	s := newTestRuleServer(t,
+		model.AggregationRule{Metric: "updated"},
+		model.AggregationRule{Metric: "deleted"},
+		model.AggregationRule{Metric: "kept"},
+	)
+	aggRules := newTestAggregationRules(t, s)
+	aggRules.SetBatchWindow(200 * time.Millisecond)
+
+	// Created outside of Terraform, so creating it again must fail.
+	s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+		return append(rules, model.AggregationRule{Metric: "conflicting"})
+	})
+
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+	var errsMu sync.Mutex
+	write := func(metric string, f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := f()
+			errsMu.Lock()
+			errs[metric] = err
+			errsMu.Unlock()
+		}()
+	}
+
+	for _, metric := range []string{"created_a", "created_b", "conflicting"} {
+		rule := model.AggregationRule{Metric: metric, Drop: true}
+		write(metric, func() error { return aggRules.Create(ctx, "", rule) })
+	}
+	write("updated", func() error {
+		return aggRules.Update(ctx, "", model.AggregationRule{Metric: "updated", Drop: true})
+	})
+	write("deleted", func() error {
+		return aggRules.Delete(ctx, "", model.AggregationRule{Metric: "deleted"})
+	})
+	wg.Wait()
+
+	require.NoError(t, errs["created_a"])
+	require.NoError(t, errs["created_b"])
+	require.NoError(t, errs["updated"])
+	require.NoError(t, errs["deleted"])
+
+	var conflict ErrRuleConflict
+	require.ErrorAs(t, errs["conflicting"], &conflict)
+	require.Equal(t, "conflicting", conflict.Metric)
+
+	require.Equal(t, 1, s.writes, "all writes should be sent as a single ruleset update")
+	// Existing rules keep their order and new rules are appended.
+	metrics := ruleMetrics(s.rules)
+	require.Len(t, metrics, 5)
+	require.Equal(t, []string{"updated", "kept", "conflicting"}, metrics[:3])
+	require.ElementsMatch(t, []string{"created_a", "created_b"}, metrics[3:])
+	require.True(t, s.rules[0].Drop)
+}
+
+func TestAggregationRulesDoesNotBatchNonExactCreates(t *testing.T) {
+	ctx := context.Background()
+	s := newTestRuleServer(t, model.AggregationRule{Metric: "kept"})
+	aggRules := newTestAggregationRules(t, s)
+	aggRules.SetBatchWindow(10 * time.Millisecond)
+
+	// Appending the rule to the ruleset could change which rule applies to a
+	// metric, so it is created on its own and the API decides where it goes.
+	rule := model.AggregationRule{Metric: "prefix_", MatchType: "prefix", Drop: true}
+	require.NoError(t, aggRules.Create(ctx, "", rule))
+
+	require.Equal(t, []string{
+		"GET /aggregations/rules",
+		"POST /aggregations/rule/prefix_",
+	}, s.requests)
+	require.Equal(t, []string{"kept", "prefix_"}, ruleMetrics(s.rules))
+}
+
+func ruleMetrics(rules []model.AggregationRule) []string {
+	metrics := make([]string, len(rules))
+	for i, rule := range rules {
+		metrics[i] = rule.Metric
+	}
+	return metrics
+}
+
+func TestAggregationRulesDropsCancelledBatchedWrites(t *testing.T) {
+	s := newTestRuleServer(t)
+	aggRules := newTestAggregationRules(t, s)
+	aggRules.SetBatchWindow(100 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- aggRules.Create(ctx, "", model.AggregationRule{Metric: "cancelled", Drop: true})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+
+	// A write queued after the cancelled one is still applied, on its own.
+	require.NoError(t, aggRules.Create(context.Background(), "", model.AggregationRule{Metric: "kept", Drop: true}))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.Equal(t, []string{"kept"}, ruleMetrics(s.rules))
+}
+
+func TestAggregationRulesReportsFlushedWritesAfterCancel(t *testing.T) {
+	s := newTestRuleServer(t)
+
+	// Hold the ruleset update until the caller has been cancelled.
+	updating := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			close(updating)
+			<-release
+		}
+		s.handle(w, r)
+	}))
+	t.Cleanup(server.Close)
+	c, err := client.New(server.URL, &client.Config{})
+	require.NoError(t, err)
+	aggRules := NewAggregationRules(c)
+	aggRules.SetBatchWindow(10 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- aggRules.Create(ctx, "", model.AggregationRule{Metric: "applied", Drop: true})
+	}()
+
+	<-updating
+	cancel()
+	select {
+	case err := <-errs:
+		t.Fatalf("returned %v before the flush finished", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+
+	require.NoError(t, <-errs)
+	require.Equal(t, []string{"applied"}, ruleMetrics(s.rules))
+}
+
+func TestAggregationRulesPreservesUnmodeledFields(t *testing.T) {
+	ctx := context.Background()
+	future := map[string]json.RawMessage{"future_field": json.RawMessage(`{"enabled":true}`)}
+
+	t.Run("ruleset update", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Extra: future})
+		aggRules := newTestAggregationRules(t, s)
+
+		rules, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"future_field"}, rules[0].UnmodeledFields())
+
+		err = aggRules.UpdateRuleSet(ctx, "", model.AggregationRuleSet{
+			{Metric: "a", Drop: true},
+			{Metric: "b", Drop: true},
+		})
+		require.NoError(t, err)
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, future, s.rules[0].Extra)
+		require.Nil(t, s.rules[1].Extra)
+	})
+
+	t.Run("rule update", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Extra: future})
+		aggRules := newTestAggregationRules(t, s)
+
+		_, err := aggRules.Read(ctx, "", "a")
+		require.NoError(t, err)
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "a", Drop: true}))
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, future, s.rules[0].Extra)
+	})
+
+	t.Run("rule update without prior read", func(t *testing.T) {
+		// terraform apply starts a fresh provider that hasn't read the rule.
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Extra: future})
+		aggRules := newTestAggregationRules(t, s)
+
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "a", Drop: true}))
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, future, s.rules[0].Extra)
+	})
+
+	t.Run("rule update after reading another rule", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a"}, model.AggregationRule{Metric: "b", Extra: future})
+		aggRules := newTestAggregationRules(t, s)
+
+		_, err := aggRules.Read(ctx, "", "a")
+		require.NoError(t, err)
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "b", Drop: true}))
+
+		require.True(t, s.rules[1].Drop)
+		require.Equal(t, future, s.rules[1].Extra)
+	})
+
+	t.Run("rule update retried after stale ETag", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a"})
+		aggRules := newTestAggregationRules(t, s)
+
+		_, err := aggRules.Read(ctx, "", "a")
+		require.NoError(t, err)
+		// The API gains a field on the rule, which makes the ETag stale.
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+			rules[0].Extra = future
+			return rules
+		})
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "a", Drop: true}))
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, future, s.rules[0].Extra)
+	})
+
+	t.Run("rule update after another rule was read with a new ETag", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a"}, model.AggregationRule{Metric: "b"})
+		aggRules := newTestAggregationRules(t, s)
+
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+			rules[1].Extra = future
+			return rules
+		})
+		// Reading a shows that the rules changed, so what we know of b is stale.
+		_, err = aggRules.Read(ctx, "", "a")
+		require.NoError(t, err)
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "b", Drop: true}))
+
+		require.True(t, s.rules[1].Drop)
+		require.Equal(t, future, s.rules[1].Extra)
+	})
+
+	t.Run("rule update after a stale ETag was refreshed", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a"}, model.AggregationRule{Metric: "b"})
+		aggRules := newTestAggregationRules(t, s)
+
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+			rules[1].Extra = future
+			return rules
+		})
+		// The update of a fails on the stale ETag, and the rules read after it
+		// are what later writes build on.
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "a", Drop: true}))
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "b", Drop: true}))
+
+		require.True(t, s.rules[1].Drop)
+		require.Equal(t, future, s.rules[1].Extra)
+	})
+
+	t.Run("ruleset update without prior read", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Extra: future})
+		aggRules := newTestAggregationRules(t, s)
+
+		require.NoError(t, aggRules.UpdateRuleSet(ctx, "", model.AggregationRuleSet{{Metric: "a", Drop: true}}))
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, future, s.rules[0].Extra)
+	})
+
+	t.Run("batched rule update", func(t *testing.T) {
+		s := newTestRuleServer(t, model.AggregationRule{Metric: "a", Extra: future})
+		aggRules := newTestAggregationRules(t, s)
+		aggRules.SetBatchWindow(10 * time.Millisecond)
+
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "a", Drop: true}))
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, future, s.rules[0].Extra)
+	})
+}
+
+func TestAggregationRulesKeysRulesByMatchType(t *testing.T) {
+	ctx := context.Background()
+	exactExtra := map[string]json.RawMessage{"future_field": json.RawMessage(`"exact"`)}
+	prefixExtra := map[string]json.RawMessage{"future_field": json.RawMessage(`"prefix"`)}
+	upstream := func() []model.AggregationRule {
+		return []model.AggregationRule{
+			{Metric: "foo", Extra: exactExtra},
+			{Metric: "foo", MatchType: "prefix", Extra: prefixExtra},
+		}
+	}
+
+	t.Run("ruleset update", func(t *testing.T) {
+		s := newTestRuleServer(t, upstream()...)
+		aggRules := newTestAggregationRules(t, s)
+
+		require.NoError(t, aggRules.UpdateRuleSet(ctx, "", model.AggregationRuleSet{
+			{Metric: "foo", MatchType: "exact", Drop: true},
+			{Metric: "foo", MatchType: "prefix", Drop: true},
+		}))
+
+		require.Equal(t, exactExtra, s.rules[0].Extra)
+		require.Equal(t, prefixExtra, s.rules[1].Extra)
+	})
+
+	t.Run("batched rule update", func(t *testing.T) {
+		s := newTestRuleServer(t, upstream()...)
+		aggRules := newTestAggregationRules(t, s)
+		aggRules.SetBatchWindow(10 * time.Millisecond)
+
+		_, err := aggRules.ReadRuleSet(ctx, "")
+		require.NoError(t, err)
+		// Only the exact rule is changed outside of Terraform, so updating
+		// the prefix rule must not be reported as a conflict.
+		s.edit(func(rules []model.AggregationRule) []model.AggregationRule {
+			rules[0].Drop = true
+			return rules
+		})
+		require.NoError(t, aggRules.Update(ctx, "", model.AggregationRule{Metric: "foo", MatchType: "prefix", Drop: true}))
+
+		require.True(t, s.rules[0].Drop)
+		require.Equal(t, exactExtra, s.rules[0].Extra)
+		require.True(t, s.rules[1].Drop)
+		require.Equal(t, prefixExtra, s.rules[1].Extra)
+	})
+
+	t.Run("single rule writes", func(t *testing.T) {
+		s := newTestRuleServer(t, upstream()...)
+		aggRules := newTestAggregationRules(t, s)
+
+		// The rule endpoints address rules by metric alone, so writing one
+		// of the rules could hit the other.
+		var conflict ErrRuleConflict
+		err := aggRules.Update(ctx, "", model.AggregationRule{Metric: "foo", MatchType: "prefix", Drop: true})
+		require.ErrorAs(t, err, &conflict)
+		require.ErrorContains(t, err, "grafana-adaptive-metrics_ruleset")
+		err = aggRules.Delete(ctx, "", model.AggregationRule{Metric: "foo", MatchType: "prefix"})
+		require.ErrorAs(t, err, &conflict)
+		err = aggRules.Create(ctx, "", model.AggregationRule{Metric: "foo", MatchType: "suffix"})
+		require.ErrorAs(t, err, &conflict)
+
+		require.Equal(t, upstream(), s.rules)
+		require.Equal(t, []string{"GET /aggregations/rules"}, s.requests)
+	})
+}
+
+func TestUnmodeledFieldsDetail(t *testing.T) {
+	_, ok := unmodeledFieldsDetail(model.AggregationRule{Metric: "a"})
+	require.False(t, ok)
+
+	detail, ok := unmodeledFieldsDetail(
+		model.AggregationRule{Metric: "a"},
+		model.AggregationRule{Metric: "b", Extra: map[string]json.RawMessage{"future_field": json.RawMessage(`1`)}},
+	)
+	require.True(t, ok)
+	require.Contains(t, detail, "- b: future_field")
+	require.NotContains(t, detail, "- a:")
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type ruleSetDatasource struct {
+	client *client.Client
+}
+
+var (
+	_ datasource.DataSource              = &ruleSetDatasource{}
+	_ datasource.DataSourceWithConfigure = &ruleSetDatasource{}
+)
+
+func newRuleSetDatasource() datasource.DataSource {
+	return &ruleSetDatasource{}
+}
+
+func (d *ruleSetDatasource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected datasource configure type",
+			fmt.Sprintf("Got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data
+}
+
+func (d *ruleSetDatasource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_ruleset", req.ProviderTypeName)
+}
+
+func (d *ruleSetDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads all aggregation rules of a segment, whether or not they are managed by this Terraform configuration.",
+		Attributes: map[string]schema.Attribute{
+			"segment": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of the segment to read the rules of.",
+			},
+			"rules": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ruleDatasourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *ruleSetDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config model.RuleSetDataTF
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, _, err := d.client.ReadAggregationRuleSet(ctx, config.Segment.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read ruleset", errorDetail(err))
+		return
+	}
+
+	state := model.AggregationRuleSet(rules).ToDataTF(config.Segment)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
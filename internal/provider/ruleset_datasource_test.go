@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRuleDatasources(t *testing.T) {
+	acc := CheckAccTestsEnabled(t)
+
+	metricName := fmt.Sprintf("test_tf_metric_%s", acc.RandString(6))
+	t.Cleanup(func() {
+		aggRules := acc.AggregationRules()
+		_ = aggRules.UpdateRuleSet(context.Background(), "", nil)
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "grafana-adaptive-metrics_ruleset" "test" {
+	rules = [{
+		metric = "%[1]s"
+		drop_labels = [ "instance" ]
+		aggregations = [ "sum" ]
+	}, {
+		metric = "%[1]s_"
+		match_type = "prefix"
+		drop = true
+	}]
+}
+
+data "grafana-adaptive-metrics_ruleset" "test" {
+	depends_on = [grafana-adaptive-metrics_ruleset.test]
+}
+
+data "grafana-adaptive-metrics_rule" "test" {
+	metric = grafana-adaptive-metrics_ruleset.test.rules[0].metric
+}
+`, metricName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.#", "2"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.0.metric", metricName),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.0.drop_labels.#", "1"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.0.managed_by", "terraform"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.1.metric", metricName+"_"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.1.match_type", "prefix"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.1.drop", "true"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_ruleset.test", "rules.1.managed_by", "terraform"),
+
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_rule.test", "metric", metricName),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_rule.test", "drop_labels.0", "instance"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_rule.test", "aggregations.0", "sum"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_rule.test", "managed_by", "terraform"),
+				),
+			},
+		},
+	})
+}
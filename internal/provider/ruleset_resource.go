@@ -11,8 +11,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 type ruleSetResource struct {
@@ -20,9 +20,10 @@ type ruleSetResource struct {
 }
 
 var (
-	_ resource.Resource                = &ruleSetResource{}
-	_ resource.ResourceWithConfigure   = &ruleSetResource{}
-	_ resource.ResourceWithImportState = &ruleSetResource{}
+	_ resource.Resource                   = &ruleSetResource{}
+	_ resource.ResourceWithConfigure      = &ruleSetResource{}
+	_ resource.ResourceWithImportState    = &ruleSetResource{}
+	_ resource.ResourceWithValidateConfig = &ruleSetResource{}
 )
 
 func newRuleSetResource() resource.Resource {
@@ -70,6 +71,64 @@ func (r *ruleSetResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+// ValidateConfig reports rules that match the same metrics as other rules of
+// the ruleset. Duplicates are rejected by the API and are errors; rules that
+// take precedence over others are only warned about.
+func (r *ruleSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rulesList types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rules"), &rulesList)...)
+	if resp.Diagnostics.HasError() || rulesList.IsNull() || rulesList.IsUnknown() {
+		return
+	}
+
+	// Only metric and match_type are read, since other attributes such as
+	// label lists may not be known until apply.
+	var configRules []types.Object
+	resp.Diagnostics.Append(rulesList.ElementsAs(ctx, &configRules, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Rules whose metric or match type isn't known yet can't be checked.
+	// indexes maps the checked rules back to their position in the config.
+	var (
+		rules   model.AggregationRuleSet
+		indexes []int
+	)
+	for i, rule := range configRules {
+		metric, _ := rule.Attributes()["metric"].(types.String)
+		matchType, _ := rule.Attributes()["match_type"].(types.String)
+		if rule.IsUnknown() || metric.IsNull() || metric.IsUnknown() || matchType.IsUnknown() {
+			continue
+		}
+		rules = append(rules, model.AggregationRule{Metric: metric.ValueString(), MatchType: matchType.ValueString()})
+		indexes = append(indexes, i)
+	}
+
+	for _, overlap := range rules.Overlaps() {
+		winner, loser := indexes[overlap.Winner], indexes[overlap.Loser]
+		loserPath := path.Root("rules").AtListIndex(loser)
+
+		switch overlap.Kind {
+		case model.OverlapDuplicate:
+			resp.Diagnostics.AddAttributeError(loserPath.AtName("metric"), "Duplicate aggregation rule",
+				fmt.Sprintf("rules[%d] matches %q, as does rules[%d]. A ruleset may only contain one rule per metric and match type.", loser, overlap.Pattern, winner))
+		case model.OverlapShadowed:
+			resp.Diagnostics.AddAttributeWarning(loserPath, "Aggregation rule never applies",
+				fmt.Sprintf("Every metric matching %q is matched by rules[%d] (%q) first, so rules[%d] never applies. Move it before rules[%d] or remove it.",
+					overlap.Pattern, winner, rules[overlap.Winner].Pattern(), loser, winner))
+		case model.OverlapExactPrecedence:
+			resp.Diagnostics.AddAttributeWarning(loserPath, "Overlapping aggregation rules",
+				fmt.Sprintf("rules[%d] (%q) matches %q exactly and takes precedence over rules[%d] (%q) for that metric.",
+					winner, rules[overlap.Winner].Pattern(), overlap.Pattern, loser, rules[overlap.Loser].Pattern()))
+		default:
+			resp.Diagnostics.AddAttributeWarning(loserPath, "Overlapping aggregation rules",
+				fmt.Sprintf("rules[%d] (%q) comes first and applies to metrics matching %q instead of rules[%d] (%q).",
+					winner, rules[overlap.Winner].Pattern(), overlap.Pattern, loser, rules[overlap.Loser].Pattern()))
+		}
+	}
+}
+
 func (r *ruleSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan model.RuleSetTF
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -78,9 +137,9 @@ func (r *ruleSetResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	// This object is a singleton per segment, so we don't need to check if it already exists.
-	err := r.rules.UpdateRuleSet(plan.Segment.ValueString(), plan.ToAPIReq())
+	err := r.rules.UpdateRuleSet(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update aggregation rule set", err.Error())
+		resp.Diagnostics.AddError("Unable to update aggregation rule set", errorDetail(err))
 		return
 	}
 
@@ -94,17 +153,21 @@ func (r *ruleSetResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	rules, err := r.rules.ReadRuleSet(state.Segment.ValueString())
+	rules, err := r.rules.ReadRuleSet(ctx, state.Segment.ValueString())
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			resp.Diagnostics.AddWarning("Ruleset not found", err.Error())
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Unable to read ruleset", err.Error())
+		resp.Diagnostics.AddError("Unable to read ruleset", errorDetail(err))
 		return
 	}
 
+	if detail, ok := unmodeledFieldsDetail(rules...); ok {
+		resp.Diagnostics.AddWarning("Aggregation rules have unsupported fields", detail)
+	}
+
 	// Prevent unnecessary drift due to reordering
 	rules = model.AlignUpstreamWithState(state.ToAPIReq(), rules)
 
@@ -126,9 +189,9 @@ func (r *ruleSetResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	err := r.rules.UpdateRuleSet(plan.Segment.ValueString(), plan.ToAPIReq())
+	err := r.rules.UpdateRuleSet(ctx, plan.Segment.ValueString(), plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update aggregation rule", err.Error())
+		resp.Diagnostics.AddError("Unable to update aggregation rule", errorDetail(err))
 		return
 	}
 
@@ -142,9 +205,9 @@ func (r *ruleSetResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.rules.UpdateRuleSet(state.Segment.ValueString(), nil)
+	err := r.rules.UpdateRuleSet(ctx, state.Segment.ValueString(), nil)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete aggregation rule", err.Error())
+		resp.Diagnostics.AddError("Unable to delete aggregation rule", errorDetail(err))
 	}
 }
 
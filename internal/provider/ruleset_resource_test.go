@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -10,16 +11,16 @@ import (
 )
 
 func TestAccRuleSetResource(t *testing.T) {
-	CheckAccTestsEnabled(t)
+	acc := CheckAccTestsEnabled(t)
 
-	metricName := fmt.Sprintf("test_tf_metric_%s", RandString(6))
+	metricName := fmt.Sprintf("test_tf_metric_%s", acc.RandString(6))
 	t.Cleanup(func() {
-		aggRules := AggregationRulesForAccTest(t)
-		_ = aggRules.UpdateRuleSet("", nil)
+		aggRules := acc.AggregationRules()
+		_ = aggRules.UpdateRuleSet(context.Background(), "", nil)
 	})
 
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
 		Steps: []resource.TestStep{
 			// Create a new ruleset
 			{
@@ -45,8 +46,8 @@ resource "grafana-adaptive-metrics_ruleset" "test" {
 			// Create + Read, no existing rule.
 			{
 				PreConfig: func() {
-					aggRules := AggregationRulesForAccTest(t)
-					require.NoError(t, aggRules.UpdateRuleSet("", nil))
+					aggRules := acc.AggregationRules()
+					require.NoError(t, aggRules.UpdateRuleSet(context.Background(), "", nil))
 				},
 				Config: providerConfig + fmt.Sprintf(`
 resource "grafana-adaptive-metrics_ruleset" "test" {
@@ -120,8 +121,8 @@ resource "grafana-adaptive-metrics_ruleset" "test" {
 			// External delete of resource, TF should recreate it.
 			{
 				PreConfig: func() {
-					aggRules := AggregationRulesForAccTest(t)
-					require.NoError(t, aggRules.UpdateRuleSet("", nil))
+					aggRules := acc.AggregationRules()
+					require.NoError(t, aggRules.UpdateRuleSet(context.Background(), "", nil))
 				},
 				Config: providerConfig + fmt.Sprintf(`
 resource "grafana-adaptive-metrics_ruleset" "test" {
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type segmentDatasource struct {
+	client *client.Client
+}
+
+var (
+	_ datasource.DataSource              = &segmentDatasource{}
+	_ datasource.DataSourceWithConfigure = &segmentDatasource{}
+)
+
+func newSegmentDatasource() datasource.DataSource {
+	return &segmentDatasource{}
+}
+
+func (d *segmentDatasource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected datasource configure type",
+			fmt.Sprintf("Got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data
+}
+
+func (d *segmentDatasource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_segment", req.ProviderTypeName)
+}
+
+func (d *segmentDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := segmentDatasourceAttributes()
+	attributes["name"] = schema.StringAttribute{
+		Required:    true,
+		Description: "The name of the segment to look up.",
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Looks up a segment by name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *segmentDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config model.SegmentTF
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segments, err := d.client.ListSegments(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to list segments", errorDetail(err))
+		return
+	}
+
+	name := config.Name.ValueString()
+	var found []model.Segment
+	for _, s := range segments {
+		if s.Name == name {
+			found = append(found, s)
+		}
+	}
+
+	if len(found) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Segment not found",
+			fmt.Sprintf("There is no segment named %q.", name))
+		return
+	}
+	if len(found) > 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Ambiguous segment name",
+			fmt.Sprintf("There are %d segments named %q. Use the grafana-adaptive-metrics_segments data source to tell them apart.", len(found), name))
+		return
+	}
+
+	state := found[0].ToTF()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -10,8 +10,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/client"
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 type segmentResource struct {
@@ -98,9 +98,9 @@ func (e *segmentResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	segment, err := e.client.CreateSegment(plan.ToAPIReq())
+	segment, err := e.client.CreateSegment(ctx, plan.ToAPIReq())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to create segment", err.Error())
+		resp.Diagnostics.AddError("Unable to create segment", errorDetail(err))
 		return
 	}
 
@@ -116,14 +116,14 @@ func (e *segmentResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	segment, err := e.client.ReadSegment(state.ID.ValueString())
+	segment, err := e.client.ReadSegment(ctx, state.ID.ValueString())
 	if err != nil {
 		if client.IsErrNotFound(err) {
 			resp.Diagnostics.AddWarning("Segment not found", err.Error())
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Unable to read segment", err.Error())
+		resp.Diagnostics.AddError("Unable to read segment", errorDetail(err))
 		return
 	}
 
@@ -149,15 +149,15 @@ func (e *segmentResource) Update(ctx context.Context, req resource.UpdateRequest
 	segment := plan.ToAPIReq()
 	segment.ID = state.ID.ValueString()
 
-	err := e.client.UpdateSegment(segment)
+	err := e.client.UpdateSegment(ctx, segment)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update segment", err.Error())
+		resp.Diagnostics.AddError("Unable to update segment", errorDetail(err))
 		return
 	}
 
-	segment, err = e.client.ReadSegment(state.ID.ValueString())
+	segment, err = e.client.ReadSegment(ctx, state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to read segment after updating", err.Error())
+		resp.Diagnostics.AddError("Unable to read segment after updating", errorDetail(err))
 		return
 	}
 
@@ -172,9 +172,9 @@ func (e *segmentResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := e.client.DeleteSegment(state.ID.ValueString())
+	err := e.client.DeleteSegment(ctx, state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete segment", err.Error())
+		resp.Diagnostics.AddError("Unable to delete segment", errorDetail(err))
 	}
 }
 
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -9,26 +10,26 @@ import (
 )
 
 func TestAccSegmentResource(t *testing.T) {
-	CheckAccTestsEnabled(t)
+	acc := CheckAccTestsEnabled(t)
 
 	t.Cleanup(func() {
-		c := ClientForAccTest(t)
-		segments, err := c.ListSegments()
+		c := acc.Client()
+		segments, err := c.ListSegments(context.Background())
 		require.NoError(t, err)
 
 		for _, s := range segments {
-			if s.ID == "01JQVN6036Z18P6Z958JNNTXRP" {
+			if s.ID == recommendationsTestSegmentID {
 				// Recommendations test segment, do not delete.
 				continue
 			}
-			err = c.DeleteSegment(s.ID)
+			err = c.DeleteSegment(context.Background(), s.ID)
 			require.NoError(t, err)
 		}
 	})
 
 	var segmentID string
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
 		Steps: []resource.TestStep{
 			// Create + Read.
 			{
@@ -72,8 +73,8 @@ resource "grafana-adaptive-metrics_segment" "test" {
 			// External delete of resource, TF should recreate it.
 			{
 				PreConfig: func() {
-					client := ClientForAccTest(t)
-					require.NoError(t, client.DeleteSegment(segmentID))
+					client := acc.Client()
+					require.NoError(t, client.DeleteSegment(context.Background(), segmentID))
 				},
 				Config: providerConfig + `
 resource "grafana-adaptive-metrics_segment" "test" {
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/client"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
+)
+
+type segmentsDatasource struct {
+	client *client.Client
+}
+
+var (
+	_ datasource.DataSource              = &segmentsDatasource{}
+	_ datasource.DataSourceWithConfigure = &segmentsDatasource{}
+)
+
+func newSegmentsDatasource() datasource.DataSource {
+	return &segmentsDatasource{}
+}
+
+func (d *segmentsDatasource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected datasource configure type",
+			fmt.Sprintf("Got %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data
+}
+
+func (d *segmentsDatasource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_segments", req.ProviderTypeName)
+}
+
+func (d *segmentsDatasource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the segments of the tenant.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the segment with this name.",
+			},
+			"selector": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list segments with this selector.",
+			},
+			"segments": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: segmentDatasourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// segmentDatasourceAttributes returns the read-only attributes of a segment.
+func segmentDatasourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed:    true,
+			Description: "A ULID that uniquely identifies the segment.",
+		},
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The name of the segment.",
+		},
+		"selector": schema.StringAttribute{
+			Computed:    true,
+			Description: "The selector that defines the segment.",
+		},
+		"fallback_to_default": schema.BoolAttribute{
+			Computed:    true,
+			Description: "Whether to fallback to the default segment if the selector does not match any segments.",
+		},
+		"auto_apply": schema.SingleNestedAttribute{
+			Computed:    true,
+			Description: privatePreviewWarning + "Configurations related to auto-applying recommendations.",
+			Attributes: map[string]schema.Attribute{
+				"enabled": schema.BoolAttribute{
+					Computed:    true,
+					Description: privatePreviewWarning + "Whether to automatically apply the generated recommendations in this segment.",
+				},
+			},
+		},
+	}
+}
+
+func (d *segmentsDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state model.SegmentListTF
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	segments, err := d.client.ListSegments(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to list segments", errorDetail(err))
+		return
+	}
+
+	state.Segments = []model.SegmentTF{}
+	for _, s := range segments {
+		if state.Matches(s) {
+			state.Segments = append(state.Segments, s.ToTF())
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
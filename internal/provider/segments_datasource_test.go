@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccSegmentDatasources(t *testing.T) {
+	acc := CheckAccTestsEnabled(t)
+
+	t.Cleanup(func() {
+		c := acc.Client()
+		segments, err := c.ListSegments(context.Background())
+		require.NoError(t, err)
+
+		for _, s := range segments {
+			if s.ID == recommendationsTestSegmentID {
+				// Recommendations test segment, do not delete.
+				continue
+			}
+			err = c.DeleteSegment(context.Background(), s.ID)
+			require.NoError(t, err)
+		}
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acc.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "grafana-adaptive-metrics_segment" "test" {
+	name = "datasource test segment"
+	selector = "{namespace=\"datasource-test\"}"
+	fallback_to_default = false
+}
+
+data "grafana-adaptive-metrics_segment" "by_name" {
+	name = grafana-adaptive-metrics_segment.test.name
+}
+
+data "grafana-adaptive-metrics_segments" "by_selector" {
+	selector = grafana-adaptive-metrics_segment.test.selector
+}
+
+data "grafana-adaptive-metrics_segments" "all" {
+	depends_on = [grafana-adaptive-metrics_segment.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.grafana-adaptive-metrics_segment.by_name", "id", "grafana-adaptive-metrics_segment.test", "id"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_segment.by_name", "selector", "{namespace=\"datasource-test\"}"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_segment.by_name", "fallback_to_default", "false"),
+
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_segments.by_selector", "segments.#", "1"),
+					resource.TestCheckResourceAttrPair("data.grafana-adaptive-metrics_segments.by_selector", "segments.0.id", "grafana-adaptive-metrics_segment.test", "id"),
+					resource.TestCheckResourceAttr("data.grafana-adaptive-metrics_segments.by_selector", "segments.0.name", "datasource test segment"),
+
+					resource.TestCheckTypeSetElemNestedAttrs("data.grafana-adaptive-metrics_segments.all", "segments.*", map[string]string{
+						"name":     "datasource test segment",
+						"selector": "{namespace=\"datasource-test\"}",
+					}),
+				),
+			},
+		},
+	})
+}
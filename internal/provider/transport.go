@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// transportConfig holds the TLS and proxy settings of the provider. Each of
+// the certificate and key settings may either be a path to a PEM file or the
+// PEM contents themselves.
+type transportConfig struct {
+	CACert             string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+	ProxyURL           string
+}
+
+func newTransport(cfg transportConfig) (*http.Transport, error) {
+	transport := cleanhttp.DefaultPooledTransport()
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACert != "" {
+		caCert, err := readPEM(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca_cert: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("ca_cert does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, errors.New("client_cert and client_key must be set together")
+		}
+
+		clientCert, err := readPEM(cfg.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client_cert: %w", err)
+		}
+		clientKey, err := readPEM(cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client_key: %w", err)
+		}
+
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client_cert or client_key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy_url %q: must be an absolute URL such as http://proxy.example.com:3128", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
+
+// readPEM returns value itself if it holds PEM contents, or else the contents
+// of the file it points to.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
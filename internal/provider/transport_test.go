@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportTLS(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte(caCert), 0o600))
+
+	get := func(t *testing.T, cfg transportConfig) error {
+		transport, err := newTransport(cfg)
+		require.NoError(t, err)
+
+		resp, err := (&http.Client{Transport: transport}).Get(s.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	require.Error(t, get(t, transportConfig{}))
+	require.NoError(t, get(t, transportConfig{CACert: caCert}))
+	require.NoError(t, get(t, transportConfig{CACert: caFile}))
+	require.NoError(t, get(t, transportConfig{InsecureSkipVerify: true}))
+}
+
+func TestTransportClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateTestCertificate(t)
+
+	var gotClientCert bool
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClientCert = len(r.TLS.PeerCertificates) == 1
+	}))
+	s.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	s.StartTLS()
+	defer s.Close()
+
+	transport, err := newTransport(transportConfig{
+		ClientCert:         certPEM,
+		ClientKey:          keyPEM,
+		InsecureSkipVerify: true,
+	})
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: transport}).Get(s.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.True(t, gotClientCert)
+
+	_, err = newTransport(transportConfig{ClientCert: certPEM})
+	require.ErrorContains(t, err, "must be set together")
+}
+
+func TestTransportProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	transport, err := newTransport(transportConfig{ProxyURL: proxy.URL})
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://adaptive-metrics.invalid/aggregations/rules")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "http://adaptive-metrics.invalid/aggregations/rules", proxied)
+
+	_, err = newTransport(transportConfig{ProxyURL: "proxy.example.com:3128"})
+	require.Error(t, err)
+}
+
+func TestTransportInvalidCACert(t *testing.T) {
+	_, err := newTransport(transportConfig{CACert: "-----BEGIN CERTIFICATE-----\nnope\n-----END CERTIFICATE-----"})
+	require.ErrorContains(t, err, "does not contain any PEM encoded certificate")
+
+	_, err = newTransport(transportConfig{CACert: filepath.Join(t.TempDir(), "missing.pem")})
+	require.ErrorContains(t, err, "could not read ca_cert")
+}
+
+func generateTestCertificate(t *testing.T) (certPEM, keyPEM string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
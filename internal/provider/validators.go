@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	// matchTypes are the accepted values of match_type. An empty match type
+	// is treated as "exact" by the API.
+	matchTypes = []string{"", "exact", "prefix", "suffix"}
+
+	// aggregationTypes are the accepted entries of aggregations.
+	aggregationTypes = []string{"sum", "count", "min", "max", "sum:counter"}
+
+	// promDurationRegexp matches durations in Prometheus syntax, e.g. "1m30s".
+	promDurationRegexp = regexp.MustCompile(`^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$`)
+)
+
+// quotedList renders values for use in validator descriptions.
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// stringOneOfValidator checks that a string is one of a fixed set of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", quotedList(v.values))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !slices.Contains(v.values, value) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid attribute value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value))
+	}
+}
+
+// listElementsOneOfValidator checks that every element of a list of strings is
+// one of a fixed set of values.
+type listElementsOneOfValidator struct {
+	values []string
+}
+
+func listElementsOneOf(values ...string) validator.List {
+	return listElementsOneOfValidator{values: values}
+}
+
+func (v listElementsOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("each element must be one of: %s", quotedList(v.values))
+}
+
+func (v listElementsOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v listElementsOneOfValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for i, elem := range req.ConfigValue.Elements() {
+		s, ok := elem.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+
+		if value := s.ValueString(); !slices.Contains(v.values, value) {
+			elemPath := req.Path.AtListIndex(i)
+			resp.Diagnostics.AddAttributeError(elemPath, "Invalid attribute value",
+				fmt.Sprintf("Attribute %s value must be one of: %s, got: %q", elemPath, quotedList(v.values), value))
+		}
+	}
+}
+
+// promDurationValidator checks that a string is empty or a duration in
+// Prometheus syntax.
+type promDurationValidator struct{}
+
+func promDuration() validator.String {
+	return promDurationValidator{}
+}
+
+func (v promDurationValidator) Description(_ context.Context) string {
+	return `value must be a duration such as "30s", "1m" or "1h30m"`
+}
+
+func (v promDurationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v promDurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); value != "" && !promDurationRegexp.MatchString(value) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value))
+	}
+}
+
+// nonEmptyStringValidator checks that a string is not empty.
+type nonEmptyStringValidator struct{}
+
+func nonEmptyString() validator.String {
+	return nonEmptyStringValidator{}
+}
+
+func (v nonEmptyStringValidator) Description(_ context.Context) string {
+	return "value must not be empty"
+}
+
+func (v nonEmptyStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v nonEmptyStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if req.ConfigValue.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid attribute value",
+			fmt.Sprintf("Attribute %s %s", req.Path, v.Description(ctx)))
+	}
+}
+
+// listConflictsWithValidator checks that a non-empty list isn't configured
+// together with a non-empty sibling list.
+type listConflictsWithValidator struct {
+	sibling string
+}
+
+func listConflictsWith(sibling string) validator.List {
+	return listConflictsWithValidator{sibling: sibling}
+}
+
+func (v listConflictsWithValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("must not be set together with %s", v.sibling)
+}
+
+func (v listConflictsWithValidator) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("must not be set together with `%s`", v.sibling)
+}
+
+func (v listConflictsWithValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || len(req.ConfigValue.Elements()) == 0 {
+		return
+	}
+
+	siblingPath := req.Path.ParentPath().AtName(v.sibling)
+	var sibling types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, siblingPath, &sibling)...)
+	if resp.Diagnostics.HasError() || sibling.IsNull() || sibling.IsUnknown() || len(sibling.Elements()) == 0 {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(req.Path, "Conflicting attributes",
+		fmt.Sprintf("Attribute %s %s", req.Path, v.Description(ctx)))
+}
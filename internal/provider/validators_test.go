@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator validator.String
+		value     types.String
+		wantErr   bool
+	}{
+		{"match type prefix", stringOneOf(matchTypes...), types.StringValue("prefix"), false},
+		{"match type empty", stringOneOf(matchTypes...), types.StringValue(""), false},
+		{"match type typo", stringOneOf(matchTypes...), types.StringValue("prefx"), true},
+		{"match type unknown", stringOneOf(matchTypes...), types.StringUnknown(), false},
+
+		{"duration empty", promDuration(), types.StringValue(""), false},
+		{"duration seconds", promDuration(), types.StringValue("30s"), false},
+		{"duration compound", promDuration(), types.StringValue("1h30m"), false},
+		{"duration milliseconds", promDuration(), types.StringValue("500ms"), false},
+		{"duration typo", promDuration(), types.StringValue("1mn"), true},
+		{"duration without unit", promDuration(), types.StringValue("60"), true},
+		{"duration go syntax", promDuration(), types.StringValue("1.5m"), true},
+		{"duration null", promDuration(), types.StringNull(), false},
+
+		{"metric", nonEmptyString(), types.StringValue("up"), false},
+		{"metric empty", nonEmptyString(), types.StringValue(""), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrPath := path.Root("rules").AtListIndex(1).AtName("attr")
+			req := validator.StringRequest{Path: attrPath, ConfigValue: tt.value}
+			var resp validator.StringResponse
+			tt.validator.ValidateString(context.Background(), req, &resp)
+
+			require.Equal(t, tt.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+			if tt.wantErr {
+				require.Len(t, resp.Diagnostics, 1)
+				require.Equal(t, attrPath, resp.Diagnostics[0].(diag.DiagnosticWithPath).Path())
+			}
+		})
+	}
+}
+
+func TestListElementsOneOf(t *testing.T) {
+	attrPath := path.Root("rules").AtListIndex(0).AtName("aggregations")
+	value := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("sum"),
+		types.StringValue("sum:countr"),
+		types.StringValue("max"),
+		types.StringValue("avg"),
+	})
+
+	var resp validator.ListResponse
+	listElementsOneOf(aggregationTypes...).ValidateList(context.Background(), validator.ListRequest{Path: attrPath, ConfigValue: value}, &resp)
+
+	require.Len(t, resp.Diagnostics, 2)
+	require.Equal(t, attrPath.AtListIndex(1), resp.Diagnostics[0].(diag.DiagnosticWithPath).Path())
+	require.Equal(t, attrPath.AtListIndex(3), resp.Diagnostics[1].(diag.DiagnosticWithPath).Path())
+}
+
+func TestListConflictsWith(t *testing.T) {
+	ctx := context.Background()
+
+	config := testRuleSetConfig(t,
+		map[string]tftypes.Value{"metric": tfString("keep_only"), "keep_labels": tfStrings("job"), "drop_labels": tfStrings()},
+		map[string]tftypes.Value{"metric": tfString("both"), "keep_labels": tfStrings("job"), "drop_labels": tfStrings("pod")},
+		map[string]tftypes.Value{"metric": tfString("drop_only"), "keep_labels": tfStrings(), "drop_labels": tfStrings("pod")},
+		map[string]tftypes.Value{"metric": tfString("neither"), "keep_labels": tfStrings(), "drop_labels": tfStrings()},
+	)
+
+	for i, name := range []string{"keep_only", "both", "drop_only", "neither"} {
+		t.Run(name, func(t *testing.T) {
+			keepPath := path.Root("rules").AtListIndex(i).AtName("keep_labels")
+			var keep types.List
+			require.False(t, config.GetAttribute(ctx, keepPath, &keep).HasError())
+
+			var resp validator.ListResponse
+			listConflictsWith("drop_labels").ValidateList(ctx, validator.ListRequest{Path: keepPath, Config: config, ConfigValue: keep}, &resp)
+
+			if name != "both" {
+				require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+				return
+			}
+			require.Len(t, resp.Diagnostics, 1)
+			require.Equal(t, keepPath, resp.Diagnostics[0].(diag.DiagnosticWithPath).Path())
+		})
+	}
+}
+
+// testRuleSetConfig builds the config of a ruleset resource. Rule attributes
+// that aren't given are null.
+func testRuleSetConfig(t *testing.T, rules ...map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	(&ruleSetResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+	s := schemaResp.Schema
+	ruleType := s.Attributes["rules"].GetType().TerraformType(ctx).(tftypes.List).ElementType.(tftypes.Object)
+
+	ruleValues := make([]tftypes.Value, len(rules))
+	for i, rule := range rules {
+		values := make(map[string]tftypes.Value, len(ruleType.AttributeTypes))
+		for name, typ := range ruleType.AttributeTypes {
+			values[name] = tftypes.NewValue(typ, nil)
+		}
+		for name, value := range rule {
+			values[name] = value
+		}
+		ruleValues[i] = tftypes.NewValue(ruleType, values)
+	}
+
+	return tfsdk.Config{
+		Schema: s,
+		Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"segment": tftypes.NewValue(tftypes.String, nil),
+			"rules":   tftypes.NewValue(tftypes.List{ElementType: ruleType}, ruleValues),
+		}),
+	}
+}
+
+func tfString(value string) tftypes.Value {
+	return tftypes.NewValue(tftypes.String, value)
+}
+
+func tfStrings(values ...string) tftypes.Value {
+	elems := make([]tftypes.Value, len(values))
+	for i, v := range values {
+		elems[i] = tfString(v)
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elems)
+}
+
+func TestRuleSetValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	config := testRuleSetConfig(t,
+		map[string]tftypes.Value{"metric": tfString("foo_"), "match_type": tfString("prefix")},
+		map[string]tftypes.Value{"metric": tfString("foo_bar_"), "match_type": tfString("prefix")},
+		map[string]tftypes.Value{"metric": tfString("up")},
+		map[string]tftypes.Value{"metric": tfString("up"), "match_type": tfString("exact")},
+		map[string]tftypes.Value{"metric": tftypes.NewValue(tftypes.String, tftypes.UnknownValue), "match_type": tfString("prefix")},
+		// Label lists and aggregations computed from other resources aren't
+		// known at plan time.
+		map[string]tftypes.Value{
+			"metric":       tfString("computed"),
+			"keep_labels":  tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+			"drop_labels":  tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+			"aggregations": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+		},
+		map[string]tftypes.Value{"metric": tfString("computed"), "drop_labels": tfStrings("pod")},
+	)
+
+	var resp resource.ValidateConfigResponse
+	(&ruleSetResource{}).ValidateConfig(ctx, resource.ValidateConfigRequest{Config: config}, &resp)
+
+	require.Len(t, resp.Diagnostics, 3, resp.Diagnostics)
+
+	require.Equal(t, diag.SeverityWarning, resp.Diagnostics[0].Severity())
+	require.Equal(t, path.Root("rules").AtListIndex(1), resp.Diagnostics[0].(diag.DiagnosticWithPath).Path())
+	require.Contains(t, resp.Diagnostics[0].Detail(), `Every metric matching "foo_bar_*" is matched by rules[0] ("foo_*") first`)
+
+	require.Equal(t, diag.SeverityError, resp.Diagnostics[1].Severity())
+	require.Equal(t, path.Root("rules").AtListIndex(3).AtName("metric"), resp.Diagnostics[1].(diag.DiagnosticWithPath).Path())
+	require.Contains(t, resp.Diagnostics[1].Detail(), `rules[3] matches "up", as does rules[2]`)
+
+	require.Equal(t, path.Root("rules").AtListIndex(6).AtName("metric"), resp.Diagnostics[2].(diag.DiagnosticWithPath).Path())
+	require.Contains(t, resp.Diagnostics[2].Detail(), `rules[6] matches "computed", as does rules[5]`)
+}
@@ -0,0 +1,269 @@
+// Package recorder implements an http.RoundTripper that records API
+// interactions into a fixture file, and replays them from it later, so that
+// tests can run deterministically without access to the API.
+//
+// Fixtures never contain credentials: only the headers the provider's logic
+// depends on are recorded, the API's host is dropped from URLs, and the
+// secrets passed to NewRecorder are scrubbed from URLs and bodies. Replayed
+// requests are scrubbed the same way before they are matched.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// scrubbed replaces secrets in recorded bodies.
+const scrubbed = "REDACTED"
+
+// recordedRequestHeaders and recordedResponseHeaders are the only headers
+// kept in fixtures.
+var (
+	recordedRequestHeaders  = []string{"If-Match"}
+	recordedResponseHeaders = []string{"Content-Type", "ETag", "Retry-After"}
+)
+
+// Interaction is a recorded request and the response the API sent to it.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper either recording interactions with the
+// API or replaying them, depending on how it was created.
+type Recorder struct {
+	path    string
+	next    http.RoundTripper
+	secrets []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	// replayed marks the interactions already replayed, so that repeated
+	// requests get the responses in the order they were recorded.
+	replayed []bool
+}
+
+// NewRecorder returns a Recorder sending requests through next and recording
+// them, to be written to path by Save. Occurrences of secrets in URLs and
+// bodies are scrubbed, and so are those of their components when they are
+// made of several, like the "<tenant>:<token>" API keys.
+func NewRecorder(path string, next http.RoundTripper, secrets ...string) *Recorder {
+	return &Recorder{path: path, next: next, secrets: scrubbedSecrets(secrets)}
+}
+
+// scrubbedSecrets returns the secrets and their ":"-separated components,
+// longest first so that a whole secret is scrubbed before its parts.
+func scrubbedSecrets(secrets []string) []string {
+	var scrubbed []string
+	for _, secret := range secrets {
+		for _, s := range append([]string{secret}, strings.Split(secret, ":")...) {
+			if s != "" && !slices.Contains(scrubbed, s) {
+				scrubbed = append(scrubbed, s)
+			}
+		}
+	}
+
+	sort.SliceStable(scrubbed, func(i, j int) bool { return len(scrubbed[i]) > len(scrubbed[j]) })
+	return scrubbed
+}
+
+// NewReplayer returns a Recorder answering requests with the interactions
+// recorded in path, without sending them anywhere. Requests are scrubbed of
+// secrets like when recording, so that they match the recorded ones.
+func NewReplayer(path string, secrets ...string) (*Recorder, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var interactions []Interaction
+	if err := json.Unmarshal(contents, &interactions); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	return &Recorder{
+		path:         path,
+		secrets:      scrubbedSecrets(secrets),
+		interactions: interactions,
+		replayed:     make([]bool, len(interactions)),
+	}, nil
+}
+
+func (r *Recorder) replaying() bool {
+	return r.next == nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	recordedReq := Request{
+		Method: req.Method,
+		URL:    r.scrub(req.URL.RequestURI()),
+		Header: pickHeaders(req.Header, recordedRequestHeaders),
+		Body:   r.scrub(string(reqBody)),
+	}
+
+	if r.replaying() {
+		return r.replay(req, recordedReq)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: recordedReq,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     pickHeaders(resp.Header, recordedResponseHeaders),
+			Body:       r.scrub(string(respBody)),
+		},
+	})
+
+	return resp, nil
+}
+
+// replay answers req with the first interaction not replayed yet whose
+// request matches it.
+func (r *Recorder) replay(req *http.Request, recordedReq Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !matches(interaction.Request, recordedReq) {
+			continue
+		}
+		r.replayed[i] = true
+
+		header := make(http.Header, len(interaction.Response.Header))
+		for k, v := range interaction.Response.Header {
+			header.Set(k, v)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction left in %s for %s %s with body %q", r.path, recordedReq.Method, recordedReq.URL, recordedReq.Body)
+}
+
+func matches(recorded, req Request) bool {
+	if recorded.Method != req.Method || recorded.URL != req.URL || recorded.Body != req.Body {
+		return false
+	}
+	for k, v := range recorded.Header {
+		if req.Header[k] != v {
+			return false
+		}
+	}
+	return len(recorded.Header) == len(req.Header)
+}
+
+// Save writes the recorded interactions to the fixture file. It is a no-op
+// when replaying.
+func (r *Recorder) Save() error {
+	if r.replaying() {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	contents, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(contents, '\n'), 0o600)
+}
+
+// Unused returns the recorded interactions that were not replayed, which
+// usually means the code under test changed since the fixture was recorded.
+func (r *Recorder) Unused() []Interaction {
+	if !r.replaying() {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, scrubbed)
+	}
+	return s
+}
+
+func pickHeaders(header http.Header, keys []string) map[string]string {
+	var picked map[string]string
+	for _, k := range keys {
+		if v := header.Get(k); v != "" {
+			if picked == nil {
+				picked = make(map[string]string)
+			}
+			picked[k] = v
+		}
+	}
+	return picked
+}
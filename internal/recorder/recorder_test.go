@@ -0,0 +1,112 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubsSecretComponents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"tenant":"1234567","echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "test.json")
+	send := func(t *testing.T, c *http.Client) string {
+		resp, err := c.Post(server.URL+"/segments?tenant=1234567", "application/json", strings.NewReader(`"1234567:secret-token"`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	rec := NewRecorder(path, http.DefaultTransport, "1234567:secret-token")
+	send(t, &http.Client{Transport: rec})
+	require.NoError(t, rec.Save())
+
+	fixture, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(fixture), "1234567")
+	require.NotContains(t, string(fixture), "secret-token")
+
+	// The replayed request still has the secrets, and matches once scrubbed.
+	replay, err := NewReplayer(path, "1234567:secret-token")
+	require.NoError(t, err)
+	require.Equal(t, `{"tenant":"REDACTED","echo":"REDACTED"}`, send(t, &http.Client{Transport: replay}))
+	require.Empty(t, replay.Unused())
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	version := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version++
+		w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+		w.Header().Set("X-Internal", "not recorded")
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `,"token":"secret-token"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "test.json")
+
+	send := func(t *testing.T, c *http.Client, body string) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/aggregations/rules?segment=a", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer secret-token")
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := c.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(respBody)
+	}
+
+	rec := NewRecorder(path, http.DefaultTransport, "secret-token")
+	resp, body := send(t, &http.Client{Transport: rec}, `"first"`)
+	require.Equal(t, `{"echo":"first","token":"secret-token"}`, body)
+	require.Equal(t, `"1"`, resp.Header.Get("ETag"))
+	send(t, &http.Client{Transport: rec}, `"first"`)
+	send(t, &http.Client{Transport: rec}, `"second"`)
+	require.NoError(t, rec.Save())
+
+	fixture, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(fixture), "secret-token")
+	require.NotContains(t, string(fixture), "X-Internal")
+	require.NotContains(t, string(fixture), server.URL)
+
+	server.Close()
+
+	replay, err := NewReplayer(path)
+	require.NoError(t, err)
+	c := &http.Client{Transport: replay}
+
+	// Repeated requests get their responses in the recorded order.
+	resp, body = send(t, c, `"first"`)
+	require.Equal(t, `{"echo":"first","token":"REDACTED"}`, body)
+	require.Equal(t, `"1"`, resp.Header.Get("ETag"))
+	resp, _ = send(t, c, `"first"`)
+	require.Equal(t, `"2"`, resp.Header.Get("ETag"))
+	require.Len(t, replay.Unused(), 1)
+
+	resp, _ = send(t, c, `"second"`)
+	require.Equal(t, `"3"`, resp.Header.Get("ETag"))
+	require.Empty(t, replay.Unused())
+
+	_, err = c.Post(server.URL+"/aggregations/rules", "application/json", strings.NewReader(`"third"`))
+	require.ErrorContains(t, err, "no recorded interaction left")
+}
@@ -0,0 +1,4 @@
+// Package model defines the types exchanged with the Adaptive Metrics API,
+// such as AggregationRule, Segment, Exemption and AggregationRecommendation,
+// along with their Terraform representations used by the provider.
+package model
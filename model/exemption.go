@@ -54,3 +54,48 @@ func (e ExemptionTF) ToAPIReq() Exemption {
 		Reason:                 e.Reason.ValueString(),
 	}
 }
+
+// ExemptionListTF is the state of the exemptions data source.
+type ExemptionListTF struct {
+	Segment    types.String      `tfsdk:"segment"`
+	Metric     types.String      `tfsdk:"metric"`
+	ManagedBy  types.String      `tfsdk:"managed_by"`
+	Exemptions []ExemptionDataTF `tfsdk:"exemptions"`
+}
+
+// Matches reports whether the exemption passes the list's filters. Filters
+// that aren't set match every exemption.
+func (tf ExemptionListTF) Matches(e Exemption) bool {
+	if !tf.Metric.IsNull() && tf.Metric.ValueString() != e.Metric {
+		return false
+	}
+	if !tf.ManagedBy.IsNull() && tf.ManagedBy.ValueString() != e.ManagedBy {
+		return false
+	}
+	return true
+}
+
+// ExemptionDataTF is an exemption as read by the exemptions data source.
+type ExemptionDataTF struct {
+	ID                     types.String   `tfsdk:"id"`
+	Metric                 types.String   `tfsdk:"metric"`
+	KeepLabels             []types.String `tfsdk:"keep_labels"`
+	DisableRecommendations types.Bool     `tfsdk:"disable_recommendations"`
+	Reason                 types.String   `tfsdk:"reason"`
+	ManagedBy              types.String   `tfsdk:"managed_by"`
+	CreatedAt              types.Int64    `tfsdk:"created_at"`
+	UpdatedAt              types.Int64    `tfsdk:"updated_at"`
+}
+
+func (e Exemption) ToDataTF() ExemptionDataTF {
+	return ExemptionDataTF{
+		ID:                     types.StringValue(e.ID),
+		Metric:                 types.StringValue(e.Metric),
+		KeepLabels:             toTypesStringSlice(e.KeepLabels),
+		DisableRecommendations: types.BoolValue(e.DisableRecommendations),
+		Reason:                 types.StringValue(e.Reason),
+		ManagedBy:              types.StringValue(e.ManagedBy),
+		CreatedAt:              types.Int64Value(e.CreatedAt.UnixMilli()),
+		UpdatedAt:              types.Int64Value(e.UpdatedAt.UnixMilli()),
+	}
+}
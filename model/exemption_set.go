@@ -0,0 +1,146 @@
+package model
+
+import (
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ExemptionSetTF struct {
+	Segment         types.String              `tfsdk:"segment"`
+	IgnoreUnmanaged types.Bool                `tfsdk:"ignore_unmanaged"`
+	Exemptions      []ExemptionSetExemptionTF `tfsdk:"exemptions"`
+}
+
+func (s ExemptionSetTF) ToAPIReq() []Exemption {
+	output := make([]Exemption, len(s.Exemptions))
+	for i, ex := range s.Exemptions {
+		output[i] = ex.ToAPIReq()
+	}
+	return output
+}
+
+// ExemptionSetExemptionTF is the subset of ExemptionTF that is configured in
+// an exemption set. Exemptions of a set are identified by their metric.
+type ExemptionSetExemptionTF struct {
+	Metric                 types.String   `tfsdk:"metric"`
+	KeepLabels             []types.String `tfsdk:"keep_labels"`
+	DisableRecommendations types.Bool     `tfsdk:"disable_recommendations"`
+	Reason                 types.String   `tfsdk:"reason"`
+}
+
+func (e ExemptionSetExemptionTF) ToAPIReq() Exemption {
+	return Exemption{
+		Metric:                 e.Metric.ValueString(),
+		KeepLabels:             toStringSlice(e.KeepLabels),
+		DisableRecommendations: e.DisableRecommendations.ValueBool(),
+		Reason:                 e.Reason.ValueString(),
+		ManagedBy:              managedByTF,
+	}
+}
+
+func (e Exemption) ToExemptionSetTF() ExemptionSetExemptionTF {
+	return ExemptionSetExemptionTF{
+		Metric:                 types.StringValue(e.Metric),
+		KeepLabels:             toTypesStringSlice(e.KeepLabels),
+		DisableRecommendations: types.BoolValue(e.DisableRecommendations),
+		Reason:                 types.StringValue(e.Reason),
+	}
+}
+
+// IsManaged reports whether the exemption was written by this provider.
+func (e Exemption) IsManaged() bool {
+	return e.ManagedBy == managedByTF
+}
+
+// sameSettings reports whether two exemptions exempt their metric the same
+// way, ignoring ids, timestamps and who manages them.
+func (e Exemption) sameSettings(other Exemption) bool {
+	return e.Metric == other.Metric &&
+		slices.Equal(e.KeepLabels, other.KeepLabels) &&
+		e.DisableRecommendations == other.DisableRecommendations &&
+		e.Reason == other.Reason
+}
+
+// ExemptionChanges are the writes that converge the exemptions of a segment
+// with the desired ones. Update and Delete carry the ids of the upstream
+// exemptions they replace.
+type ExemptionChanges struct {
+	Create []Exemption
+	Update []Exemption
+	Delete []Exemption
+}
+
+// DiffExemptions matches the desired exemptions to the upstream ones by
+// metric. Desired exemptions without an upstream one are created, and those
+// whose upstream one differs or isn't managed by Terraform are updated.
+// Upstream exemptions that aren't desired are deleted, except for unmanaged
+// ones when ignoreUnmanaged is set.
+func DiffExemptions(upstream []Exemption, desired []Exemption, ignoreUnmanaged bool) ExemptionChanges {
+	var changes ExemptionChanges
+
+	wanted := make(map[string]struct{}, len(desired))
+	for _, ex := range desired {
+		wanted[ex.Metric] = struct{}{}
+	}
+
+	// The API shouldn't return two exemptions for a metric, but if it does
+	// the first one is matched and the others are treated as undesired.
+	byMetric := make(map[string]Exemption, len(upstream))
+	for _, ex := range upstream {
+		_, duplicate := byMetric[ex.Metric]
+		if !duplicate {
+			byMetric[ex.Metric] = ex
+		}
+		if _, ok := wanted[ex.Metric]; ok && !duplicate {
+			continue
+		}
+		if ignoreUnmanaged && !ex.IsManaged() {
+			continue
+		}
+		changes.Delete = append(changes.Delete, ex)
+	}
+
+	for _, ex := range desired {
+		current, ok := byMetric[ex.Metric]
+		if !ok {
+			changes.Create = append(changes.Create, ex)
+			continue
+		}
+		if !current.sameSettings(ex) || !current.IsManaged() {
+			ex.ID = current.ID
+			changes.Update = append(changes.Update, ex)
+		}
+	}
+
+	return changes
+}
+
+// AlignExemptionsWithState orders the upstream exemptions like the state, by
+// metric. Upstream exemptions missing from the state come last, in their
+// upstream order.
+func AlignExemptionsWithState(state []Exemption, upstream []Exemption) []Exemption {
+	position := make(map[string]int, len(state))
+	for i, ex := range state {
+		if _, ok := position[ex.Metric]; !ok {
+			position[ex.Metric] = i
+		}
+	}
+
+	output := slices.Clone(upstream)
+	slices.SortStableFunc(output, func(a, b Exemption) int {
+		aPos, aOK := position[a.Metric]
+		bPos, bOK := position[b.Metric]
+		switch {
+		case aOK && bOK:
+			return aPos - bPos
+		case aOK:
+			return -1
+		case bOK:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return output
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffExemptions(t *testing.T) {
+	upstream := []Exemption{
+		{ID: "1", Metric: "unchanged", KeepLabels: []string{"job"}, ManagedBy: "terraform"},
+		{ID: "2", Metric: "changed", Reason: "old", ManagedBy: "terraform"},
+		{ID: "3", Metric: "removed", ManagedBy: "terraform"},
+		{ID: "4", Metric: "from_ui", ManagedBy: "ui"},
+		{ID: "5", Metric: "adopted", ManagedBy: ""},
+	}
+	desired := []Exemption{
+		{Metric: "unchanged", KeepLabels: []string{"job"}, ManagedBy: "terraform"},
+		{Metric: "changed", Reason: "new", ManagedBy: "terraform"},
+		{Metric: "adopted", ManagedBy: "terraform"},
+		{Metric: "added", DisableRecommendations: true, ManagedBy: "terraform"},
+	}
+
+	t.Run("authoritative", func(t *testing.T) {
+		changes := DiffExemptions(upstream, desired, false)
+
+		assert.Equal(t, []Exemption{desired[3]}, changes.Create)
+		assert.Equal(t, []Exemption{
+			{ID: "2", Metric: "changed", Reason: "new", ManagedBy: "terraform"},
+			{ID: "5", Metric: "adopted", ManagedBy: "terraform"},
+		}, changes.Update)
+		assert.Equal(t, []Exemption{upstream[2], upstream[3]}, changes.Delete)
+	})
+
+	t.Run("ignore unmanaged", func(t *testing.T) {
+		changes := DiffExemptions(upstream, desired, true)
+
+		assert.Equal(t, []Exemption{desired[3]}, changes.Create)
+		assert.Len(t, changes.Update, 2)
+		assert.Equal(t, []Exemption{upstream[2]}, changes.Delete)
+	})
+
+	t.Run("nothing desired", func(t *testing.T) {
+		changes := DiffExemptions(upstream, nil, true)
+
+		assert.Empty(t, changes.Create)
+		assert.Empty(t, changes.Update)
+		assert.Equal(t, []Exemption{upstream[0], upstream[1], upstream[2]}, changes.Delete)
+	})
+
+	t.Run("duplicate upstream", func(t *testing.T) {
+		duplicated := []Exemption{
+			{ID: "1", Metric: "up", ManagedBy: "terraform"},
+			{ID: "2", Metric: "up", ManagedBy: "terraform"},
+		}
+		changes := DiffExemptions(duplicated, []Exemption{{Metric: "up", ManagedBy: "terraform"}}, false)
+
+		assert.Empty(t, changes.Create)
+		assert.Empty(t, changes.Update)
+		assert.Equal(t, []Exemption{duplicated[1]}, changes.Delete)
+	})
+}
+
+func TestAlignExemptionsWithState(t *testing.T) {
+	state := []Exemption{{Metric: "c"}, {Metric: "a"}, {Metric: "gone"}}
+	upstream := []Exemption{{Metric: "a"}, {Metric: "new_1"}, {Metric: "c"}, {Metric: "new_2"}}
+
+	aligned := AlignExemptionsWithState(state, upstream)
+
+	assert.Equal(t, []Exemption{{Metric: "c"}, {Metric: "a"}, {Metric: "new_1"}, {Metric: "new_2"}}, aligned)
+	assert.Equal(t, Exemption{Metric: "a"}, upstream[0], "upstream must not be reordered in place")
+}
+
+func TestExemptionSetExemptionTF_RoundTrip(t *testing.T) {
+	ex := Exemption{
+		Metric:                 "up",
+		KeepLabels:             []string{"job"},
+		DisableRecommendations: true,
+		Reason:                 "alerting",
+		ManagedBy:              "terraform",
+	}
+
+	assert.Equal(t, ex, ex.ToExemptionSetTF().ToAPIReq())
+}
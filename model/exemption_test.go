@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExemptionListTF_Matches(t *testing.T) {
+	exemption := Exemption{Metric: "up", ManagedBy: "terraform"}
+
+	assert.True(t, ExemptionListTF{Metric: types.StringNull(), ManagedBy: types.StringNull()}.Matches(exemption))
+	assert.True(t, ExemptionListTF{Metric: types.StringValue("up"), ManagedBy: types.StringNull()}.Matches(exemption))
+	assert.False(t, ExemptionListTF{Metric: types.StringValue("down"), ManagedBy: types.StringNull()}.Matches(exemption))
+	assert.True(t, ExemptionListTF{Metric: types.StringNull(), ManagedBy: types.StringValue("terraform")}.Matches(exemption))
+	assert.False(t, ExemptionListTF{Metric: types.StringValue("up"), ManagedBy: types.StringValue("ui")}.Matches(exemption))
+	assert.False(t, ExemptionListTF{Metric: types.StringNull(), ManagedBy: types.StringValue("")}.Matches(exemption))
+}
+
+func TestExemption_ToDataTF(t *testing.T) {
+	created := time.UnixMilli(1700000000000)
+	updated := time.UnixMilli(1700000060000)
+	exemption := Exemption{
+		ID:                     "01HZ",
+		Metric:                 "up",
+		KeepLabels:             []string{"job"},
+		DisableRecommendations: true,
+		Reason:                 "needed for alerting",
+		ManagedBy:              "ui",
+		CreatedAt:              created,
+		UpdatedAt:              updated,
+	}
+
+	assert.Equal(t, ExemptionDataTF{
+		ID:                     types.StringValue("01HZ"),
+		Metric:                 types.StringValue("up"),
+		KeepLabels:             []types.String{types.StringValue("job")},
+		DisableRecommendations: types.BoolValue(true),
+		Reason:                 types.StringValue("needed for alerting"),
+		ManagedBy:              types.StringValue("ui"),
+		CreatedAt:              types.Int64Value(1700000000000),
+		UpdatedAt:              types.Int64Value(1700000060000),
+	}, exemption.ToDataTF())
+}
@@ -0,0 +1,63 @@
+package model
+
+import "strings"
+
+// Match returns the rule that applies to metric, and false if there is none.
+// Like the API, it gives exact rules precedence over prefix and suffix rules,
+// and otherwise picks the first rule in the list whose pattern matches.
+func (a AggregationRuleSet) Match(metric string) (AggregationRule, bool) {
+	i := a.matchIndex(metric)
+	if i < 0 {
+		return AggregationRule{}, false
+	}
+	return a[i], true
+}
+
+func (a AggregationRuleSet) matchIndex(metric string) int {
+	first := -1
+	for i, rule := range a {
+		if !rule.Matches(metric) {
+			continue
+		}
+		if rule.IsExactMatch() {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	return first
+}
+
+// Pattern renders the metrics the rule matches, e.g. "foo_*" for a prefix rule.
+func (r AggregationRule) Pattern() string {
+	switch r.normalizedMatchType() {
+	case "prefix":
+		return r.Metric + "*"
+	case "suffix":
+		return "*" + r.Metric
+	default:
+		return r.Metric
+	}
+}
+
+// Matches reports whether metric matches the rule's pattern. Whether the rule
+// applies to the metric also depends on the other rules of its ruleset; see
+// AggregationRuleSet.Match.
+func (r AggregationRule) Matches(metric string) bool {
+	switch r.normalizedMatchType() {
+	case "prefix":
+		return strings.HasPrefix(metric, r.Metric)
+	case "suffix":
+		return strings.HasSuffix(metric, r.Metric)
+	default:
+		return metric == r.Metric
+	}
+}
+
+func (r AggregationRule) normalizedMatchType() string {
+	if r.IsExactMatch() {
+		return "exact"
+	}
+	return r.MatchType
+}
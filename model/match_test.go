@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregationRuleSet_Match(t *testing.T) {
+	rules := AggregationRuleSet{
+		{Metric: "foo_bar_", MatchType: "prefix", Drop: true},
+		{Metric: "foo_", MatchType: "prefix"},
+		{Metric: "_total", MatchType: "suffix"},
+		{Metric: "foo_bar_baz"},
+		{Metric: "up", MatchType: "exact"},
+	}
+
+	tests := []struct {
+		metric   string
+		expected int
+	}{
+		{metric: "up", expected: 4},
+		{metric: "foo_bar_baz", expected: 3},
+		{metric: "foo_bar_qux", expected: 0},
+		{metric: "foo_qux", expected: 1},
+		// The prefix rule comes before the suffix rule.
+		{metric: "foo_requests_total", expected: 1},
+		{metric: "http_requests_total", expected: 2},
+		{metric: "down", expected: -1},
+		{metric: "up_total", expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			rule, ok := rules.Match(tt.metric)
+			if tt.expected < 0 {
+				assert.False(t, ok)
+				assert.Equal(t, AggregationRule{}, rule)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, rules[tt.expected], rule)
+		})
+	}
+}
+
+func TestAggregationRuleSet_MatchPrefersFirstExactRule(t *testing.T) {
+	rules := AggregationRuleSet{
+		{Metric: "up", MatchType: "prefix"},
+		{Metric: "up", Drop: true},
+		{Metric: "up", MatchType: "exact"},
+	}
+
+	rule, ok := rules.Match("up")
+	assert.True(t, ok)
+	assert.Equal(t, rules[1], rule)
+}
+
+func TestAggregationRule_Matches(t *testing.T) {
+	assert.True(t, AggregationRule{Metric: "a"}.Matches("a"))
+	assert.False(t, AggregationRule{Metric: "a"}.Matches("ab"))
+	assert.True(t, AggregationRule{Metric: "a", MatchType: "prefix"}.Matches("ab"))
+	assert.False(t, AggregationRule{Metric: "a", MatchType: "prefix"}.Matches("ba"))
+	assert.True(t, AggregationRule{Metric: "a", MatchType: "suffix"}.Matches("ba"))
+	assert.False(t, AggregationRule{Metric: "a", MatchType: "suffix"}.Matches("ab"))
+}
+
+func TestAggregationRule_Pattern(t *testing.T) {
+	assert.Equal(t, "a", AggregationRule{Metric: "a"}.Pattern())
+	assert.Equal(t, "a", AggregationRule{Metric: "a", MatchType: "exact"}.Pattern())
+	assert.Equal(t, "a*", AggregationRule{Metric: "a", MatchType: "prefix"}.Pattern())
+	assert.Equal(t, "*a", AggregationRule{Metric: "a", MatchType: "suffix"}.Pattern())
+}
@@ -0,0 +1,104 @@
+package model
+
+import "fmt"
+
+// RuleOverlapKind classifies how two rules of a ruleset overlap.
+type RuleOverlapKind int
+
+const (
+	// OverlapDuplicate means two rules have the same metric and match type.
+	// The API rejects such rulesets.
+	OverlapDuplicate RuleOverlapKind = iota
+	// OverlapShadowed means the losing rule can never apply, because every
+	// metric it matches is matched by the winning rule first.
+	OverlapShadowed
+	// OverlapPartial means the winning rule applies to some of the metrics
+	// the losing rule matches.
+	OverlapPartial
+	// OverlapExactPrecedence means an exact rule takes precedence over a
+	// prefix or suffix rule for the metric it names.
+	OverlapExactPrecedence
+)
+
+// RuleOverlap describes two rules of a ruleset that match some of the same
+// metrics. Winner and Loser are indexes into the ruleset, and Pattern
+// describes the metrics the winner takes from the loser, with "*" standing
+// for any sequence of characters.
+type RuleOverlap struct {
+	Kind    RuleOverlapKind
+	Winner  int
+	Loser   int
+	Pattern string
+}
+
+// Overlaps returns the pairs of rules in the ruleset that match some of the
+// same metrics. The winner of a pair is the rule Match picks for those
+// metrics. Prefix and suffix rules are only compared to rules of the same
+// match type, since any prefix rule overlaps with any suffix rule.
+func (a AggregationRuleSet) Overlaps() []RuleOverlap {
+	var overlaps []RuleOverlap
+	for j, later := range a {
+		for i, earlier := range a[:j] {
+			if overlap, ok := compareRules(i, earlier, j, later); ok {
+				overlaps = append(overlaps, overlap)
+			}
+		}
+	}
+	return overlaps
+}
+
+func compareRules(i int, a AggregationRule, j int, b AggregationRule) (RuleOverlap, bool) {
+	if a.Key() == b.Key() {
+		return RuleOverlap{Kind: OverlapDuplicate, Winner: i, Loser: j, Pattern: a.Pattern()}, true
+	}
+
+	// Find a metric both rules match, if any: the metric of an exact rule,
+	// or the more specific of two prefixes or suffixes.
+	var metric string
+	switch aType, bType := a.normalizedMatchType(), b.normalizedMatchType(); {
+	case aType == "exact":
+		metric = a.Metric
+	case bType == "exact":
+		metric = b.Metric
+	case aType != bType:
+		return RuleOverlap{}, false
+	case len(a.Metric) > len(b.Metric):
+		metric = a.Metric
+	default:
+		metric = b.Metric
+	}
+	if !a.Matches(metric) || !b.Matches(metric) {
+		return RuleOverlap{}, false
+	}
+
+	indexes := [2]int{i, j}
+	pair := AggregationRuleSet{a, b}
+	w := pair.matchIndex(metric)
+	winner, loser := pair[w], pair[1-w]
+	overlap := RuleOverlap{Winner: indexes[w], Loser: indexes[1-w]}
+
+	switch {
+	case winner.IsExactMatch() || loser.IsExactMatch():
+		overlap.Kind, overlap.Pattern = OverlapExactPrecedence, metric
+	case winner.Matches(loser.Metric):
+		// Every metric the loser matches is more specific than the winner's
+		// pattern.
+		overlap.Kind, overlap.Pattern = OverlapShadowed, loser.Pattern()
+	default:
+		overlap.Kind, overlap.Pattern = OverlapPartial, winner.Pattern()
+	}
+	return overlap, true
+}
+
+func (o RuleOverlap) String() string {
+	switch o.Kind {
+	case OverlapDuplicate:
+		return fmt.Sprintf("rules %d and %d both match %q", o.Winner, o.Loser, o.Pattern)
+	case OverlapShadowed:
+		return fmt.Sprintf("rule %d is shadowed by rule %d, which matches every metric matching %q first", o.Loser, o.Winner, o.Pattern)
+	case OverlapExactPrecedence:
+		return fmt.Sprintf("rule %d takes precedence over rule %d for %q", o.Winner, o.Loser, o.Pattern)
+	default:
+		return fmt.Sprintf("rule %d takes precedence over rule %d for metrics matching %q", o.Winner, o.Loser, o.Pattern)
+	}
+}
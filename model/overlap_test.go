@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregationRuleSet_Overlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    AggregationRuleSet
+		expected []RuleOverlap
+	}{
+		{
+			name: "unrelated rules",
+			rules: AggregationRuleSet{
+				{Metric: "a"},
+				{Metric: "b", MatchType: "exact"},
+				{Metric: "foo_", MatchType: "prefix"},
+				{Metric: "bar_", MatchType: "prefix"},
+				{Metric: "_total", MatchType: "suffix"},
+			},
+		},
+		{
+			name: "duplicates treat empty match type as exact",
+			rules: AggregationRuleSet{
+				{Metric: "a"},
+				{Metric: "a", MatchType: "exact"},
+				{Metric: "a", MatchType: "prefix"},
+			},
+			expected: []RuleOverlap{
+				{Kind: OverlapDuplicate, Winner: 0, Loser: 1, Pattern: "a"},
+				{Kind: OverlapExactPrecedence, Winner: 0, Loser: 2, Pattern: "a"},
+				{Kind: OverlapExactPrecedence, Winner: 1, Loser: 2, Pattern: "a"},
+			},
+		},
+		{
+			name: "broader prefix first shadows narrower prefix",
+			rules: AggregationRuleSet{
+				{Metric: "foo_", MatchType: "prefix"},
+				{Metric: "foo_bar_", MatchType: "prefix"},
+			},
+			expected: []RuleOverlap{
+				{Kind: OverlapShadowed, Winner: 0, Loser: 1, Pattern: "foo_bar_*"},
+			},
+		},
+		{
+			name: "narrower prefix first takes part of broader prefix",
+			rules: AggregationRuleSet{
+				{Metric: "foo_bar_", MatchType: "prefix"},
+				{Metric: "foo_", MatchType: "prefix"},
+			},
+			expected: []RuleOverlap{
+				{Kind: OverlapPartial, Winner: 0, Loser: 1, Pattern: "foo_bar_*"},
+			},
+		},
+		{
+			name: "suffixes",
+			rules: AggregationRuleSet{
+				{Metric: "_total", MatchType: "suffix"},
+				{Metric: "_errors_total", MatchType: "suffix"},
+			},
+			expected: []RuleOverlap{
+				{Kind: OverlapShadowed, Winner: 0, Loser: 1, Pattern: "*_errors_total"},
+			},
+		},
+		{
+			name: "exact rule wins regardless of position",
+			rules: AggregationRuleSet{
+				{Metric: "foo_", MatchType: "prefix"},
+				{Metric: "foo_bar"},
+				{Metric: "baz"},
+			},
+			expected: []RuleOverlap{
+				{Kind: OverlapExactPrecedence, Winner: 1, Loser: 0, Pattern: "foo_bar"},
+			},
+		},
+		{
+			name: "prefix and suffix rules are not compared",
+			rules: AggregationRuleSet{
+				{Metric: "foo_", MatchType: "prefix"},
+				{Metric: "_total", MatchType: "suffix"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rules.Overlaps())
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package model
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type AggregationRecommendation struct {
 	AggregationRule
@@ -15,6 +20,84 @@ type AggregationRecommendation struct {
 	TotalSeriesBeforeAggregation int64    `json:"total_series_before_aggregation,omitempty"`
 }
 
+// recommendationJSON has the fields a recommendation adds to its rule. The
+// embedded AggregationRule's JSON methods would otherwise be promoted and
+// encode only the rule.
+type recommendationJSON struct {
+	RecommendedAction  string `json:"recommended_action"`
+	UsagesInRules      int64  `json:"usages_in_rules"`
+	UsagesInQueries    int64  `json:"usages_in_queries"`
+	UsagesInDashboards int64  `json:"usages_in_dashboards"`
+
+	KeptLabels                   []string `json:"kept_labels,omitempty"`
+	TotalSeriesAfterAggregation  int64    `json:"total_series_after_aggregation,omitempty"`
+	TotalSeriesBeforeAggregation int64    `json:"total_series_before_aggregation,omitempty"`
+}
+
+var recommendationFields = jsonFieldNames(reflect.TypeOf(recommendationJSON{}))
+
+func (r *AggregationRecommendation) UnmarshalJSON(data []byte) error {
+	var rule AggregationRule
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+	var rec recommendationJSON
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+
+	for name := range recommendationFields {
+		delete(rule.Extra, name)
+	}
+	if len(rule.Extra) == 0 {
+		rule.Extra = nil
+	}
+
+	*r = AggregationRecommendation{
+		AggregationRule: rule,
+
+		RecommendedAction:  rec.RecommendedAction,
+		UsagesInRules:      rec.UsagesInRules,
+		UsagesInQueries:    rec.UsagesInQueries,
+		UsagesInDashboards: rec.UsagesInDashboards,
+
+		KeptLabels:                   rec.KeptLabels,
+		TotalSeriesAfterAggregation:  rec.TotalSeriesAfterAggregation,
+		TotalSeriesBeforeAggregation: rec.TotalSeriesBeforeAggregation,
+	}
+	return nil
+}
+
+func (r AggregationRecommendation) MarshalJSON() ([]byte, error) {
+	rec, err := json.Marshal(recommendationJSON{
+		RecommendedAction:  r.RecommendedAction,
+		UsagesInRules:      r.UsagesInRules,
+		UsagesInQueries:    r.UsagesInQueries,
+		UsagesInDashboards: r.UsagesInDashboards,
+
+		KeptLabels:                   r.KeptLabels,
+		TotalSeriesAfterAggregation:  r.TotalSeriesAfterAggregation,
+		TotalSeriesBeforeAggregation: r.TotalSeriesBeforeAggregation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var extra map[string]json.RawMessage
+	if err := json.Unmarshal(rec, &extra); err != nil {
+		return nil, err
+	}
+	for name, value := range r.Extra {
+		if _, ok := recommendationFields[name]; !ok {
+			extra[name] = value
+		}
+	}
+
+	rule := r.AggregationRule
+	rule.Extra = extra
+	return rule.MarshalJSON()
+}
+
 func (r *AggregationRecommendation) ToTF() AggregationRecommendationTF {
 	return AggregationRecommendationTF{
 		Metric:    types.StringValue(r.Metric),
@@ -29,6 +112,8 @@ func (r *AggregationRecommendation) ToTF() AggregationRecommendationTF {
 		AggregationInterval: types.StringValue(r.AggregationInterval),
 		AggregationDelay:    types.StringValue(r.AggregationDelay),
 
+		Ingest: types.BoolValue(r.Ingest),
+
 		RecommendedAction:  types.StringValue(r.RecommendedAction),
 		UsagesInRules:      types.Int64Value(r.UsagesInRules),
 		UsagesInQueries:    types.Int64Value(r.UsagesInQueries),
@@ -69,6 +154,8 @@ type AggregationRecommendationTF struct {
 	AggregationInterval types.String `tfsdk:"aggregation_interval"`
 	AggregationDelay    types.String `tfsdk:"aggregation_delay"`
 
+	Ingest types.Bool `tfsdk:"ingest"`
+
 	RecommendedAction  types.String `tfsdk:"recommended_action"`
 	UsagesInRules      types.Int64  `tfsdk:"usages_in_rules"`
 	UsagesInQueries    types.Int64  `tfsdk:"usages_in_queries"`
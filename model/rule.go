@@ -0,0 +1,227 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const managedByTF = "terraform"
+
+type SegmentedRuleSet struct {
+	Etag    string            `json:"etag"`
+	Segment Segment           `json:"segment"`
+	Rules   []AggregationRule `json:"rules"`
+}
+
+type AggregationRule struct {
+	Metric    string `json:"metric"`
+	MatchType string `json:"match_type,omitempty"`
+
+	Drop       bool     `json:"drop,omitempty"`
+	KeepLabels []string `json:"keep_labels,omitempty"`
+	DropLabels []string `json:"drop_labels,omitempty"`
+
+	Aggregations []string `json:"aggregations,omitempty"`
+
+	AggregationInterval string `json:"aggregation_interval,omitempty"`
+	AggregationDelay    string `json:"aggregation_delay,omitempty"`
+
+	ManagedBy string `json:"managed_by,omitempty"`
+
+	Ingest bool `json:"ingest,omitempty"`
+
+	// Extra holds the fields the API returned that this version of the
+	// provider does not know about. They are written back unchanged so that a
+	// full replace of the rules does not wipe them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// aggregationRuleJSON has the fields of AggregationRule without its JSON
+// methods, so it can be used to encode and decode the known fields.
+type aggregationRuleJSON AggregationRule
+
+// aggregationRuleFields are the JSON names of the fields AggregationRule models.
+var aggregationRuleFields = jsonFieldNames(reflect.TypeOf(AggregationRule{}))
+
+func (r *AggregationRule) UnmarshalJSON(data []byte) error {
+	var known aggregationRuleJSON
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for name := range aggregationRuleFields {
+		delete(all, name)
+	}
+
+	known.Extra = nil
+	if len(all) > 0 {
+		known.Extra = all
+	}
+	*r = AggregationRule(known)
+	return nil
+}
+
+func (r AggregationRule) MarshalJSON() ([]byte, error) {
+	known, err := json.Marshal(aggregationRuleJSON(r))
+	if err != nil || len(r.Extra) == 0 {
+		return known, err
+	}
+	return mergeJSONObject(known, r.Extra, aggregationRuleFields)
+}
+
+// WithUnmodeledFields returns a copy of r that carries over the fields of prev
+// the Terraform schema cannot express. prev is expected to be the last known
+// upstream version of the same rule.
+func (r AggregationRule) WithUnmodeledFields(prev AggregationRule) AggregationRule {
+	if len(r.Extra) == 0 {
+		r.Extra = prev.Extra
+	}
+	return r
+}
+
+// UnmodeledFields returns the sorted names of the fields set on the rule that
+// the Terraform schema cannot express.
+func (r AggregationRule) UnmodeledFields() []string {
+	var fields []string
+	for name := range r.Extra {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (r AggregationRule) ToTF() RuleTF {
+	return RuleTF{
+		Metric:    types.StringValue(r.Metric),
+		MatchType: types.StringValue(r.MatchType),
+
+		Drop:       types.BoolValue(r.Drop),
+		KeepLabels: toTypesStringSlice(r.KeepLabels),
+		DropLabels: toTypesStringSlice(r.DropLabels),
+
+		Aggregations: toTypesStringSlice(r.Aggregations),
+
+		AggregationInterval: types.StringValue(r.AggregationInterval),
+		AggregationDelay:    types.StringValue(r.AggregationDelay),
+
+		Ingest: types.BoolValue(r.Ingest),
+	}
+}
+
+func (r AggregationRule) ToRuleSetRuleTF() RuleSetRuleTF {
+	return RuleSetRuleTF{
+		Metric:    types.StringValue(r.Metric),
+		MatchType: types.StringValue(r.MatchType),
+
+		Drop:       types.BoolValue(r.Drop),
+		KeepLabels: toTypesStringSlice(r.KeepLabels),
+		DropLabels: toTypesStringSlice(r.DropLabels),
+
+		Aggregations: toTypesStringSlice(r.Aggregations),
+
+		AggregationInterval: types.StringValue(r.AggregationInterval),
+		AggregationDelay:    types.StringValue(r.AggregationDelay),
+
+		Ingest: types.BoolValue(r.Ingest),
+	}
+}
+
+type RuleTF struct {
+	Segment   types.String `tfsdk:"segment"`
+	Metric    types.String `tfsdk:"metric"`
+	MatchType types.String `tfsdk:"match_type"`
+
+	Drop       types.Bool     `tfsdk:"drop"`
+	KeepLabels []types.String `tfsdk:"keep_labels"`
+	DropLabels []types.String `tfsdk:"drop_labels"`
+
+	Aggregations []types.String `tfsdk:"aggregations"`
+
+	AggregationInterval types.String `tfsdk:"aggregation_interval"`
+	AggregationDelay    types.String `tfsdk:"aggregation_delay"`
+
+	Ingest types.Bool `tfsdk:"ingest"`
+
+	AutoImport types.Bool `tfsdk:"auto_import"`
+
+	LastUpdated types.String `tfsdk:"-"`
+}
+
+func (r RuleTF) ToAPIReq() AggregationRule {
+	return AggregationRule{
+		Metric:    r.Metric.ValueString(),
+		MatchType: r.MatchType.ValueString(),
+
+		Drop:       r.Drop.ValueBool(),
+		KeepLabels: toStringSlice(r.KeepLabels),
+		DropLabels: toStringSlice(r.DropLabels),
+
+		Aggregations: toStringSlice(r.Aggregations),
+
+		AggregationInterval: r.AggregationInterval.ValueString(),
+		AggregationDelay:    r.AggregationDelay.ValueString(),
+
+		Ingest: r.Ingest.ValueBool(),
+
+		ManagedBy: managedByTF,
+	}
+}
+
+// Equal reports whether two rules have the same content. Missing and empty
+// lists are considered equal, as are the "" and "exact" match types. Fields
+// the provider does not model are ignored, since the API may add them to a
+// rule after it is written.
+func (r AggregationRule) Equal(other AggregationRule) bool {
+	return r.Metric == other.Metric &&
+		(r.MatchType == other.MatchType || r.IsExactMatch() && other.IsExactMatch()) &&
+		r.Drop == other.Drop &&
+		slices.Equal(r.KeepLabels, other.KeepLabels) &&
+		slices.Equal(r.DropLabels, other.DropLabels) &&
+		slices.Equal(r.Aggregations, other.Aggregations) &&
+		r.AggregationInterval == other.AggregationInterval &&
+		r.AggregationDelay == other.AggregationDelay &&
+		r.ManagedBy == other.ManagedBy &&
+		r.Ingest == other.Ingest
+}
+
+// jsonFieldNames returns the JSON names of the exported fields of struct type t.
+func jsonFieldNames(t reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// mergeJSONObject adds the extra fields to the JSON object in known, skipping
+// any field named in reserved. The result has its keys sorted.
+func mergeJSONObject(known []byte, extra map[string]json.RawMessage, reserved map[string]struct{}) ([]byte, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(known, &all); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := reserved[name]; ok {
+			continue
+		}
+		if _, ok := all[name]; ok {
+			continue
+		}
+		all[name] = value
+	}
+	return json.Marshal(all)
+}
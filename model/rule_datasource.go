@@ -0,0 +1,98 @@
+package model
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+// RuleDataTF is a rule as read by the rule data source.
+type RuleDataTF struct {
+	// Note: these fields are copied from RuleTF because tfsdk doesn't support struct embedding.
+	Segment   types.String `tfsdk:"segment"`
+	Metric    types.String `tfsdk:"metric"`
+	MatchType types.String `tfsdk:"match_type"`
+
+	Drop       types.Bool     `tfsdk:"drop"`
+	KeepLabels []types.String `tfsdk:"keep_labels"`
+	DropLabels []types.String `tfsdk:"drop_labels"`
+
+	Aggregations []types.String `tfsdk:"aggregations"`
+
+	AggregationInterval types.String `tfsdk:"aggregation_interval"`
+	AggregationDelay    types.String `tfsdk:"aggregation_delay"`
+
+	Ingest types.Bool `tfsdk:"ingest"`
+
+	ManagedBy types.String `tfsdk:"managed_by"`
+}
+
+func (r AggregationRule) ToRuleDataTF(segment types.String) RuleDataTF {
+	return RuleDataTF{
+		Segment:   segment,
+		Metric:    types.StringValue(r.Metric),
+		MatchType: types.StringValue(r.MatchType),
+
+		Drop:       types.BoolValue(r.Drop),
+		KeepLabels: toTypesStringSlice(r.KeepLabels),
+		DropLabels: toTypesStringSlice(r.DropLabels),
+
+		Aggregations: toTypesStringSlice(r.Aggregations),
+
+		AggregationInterval: types.StringValue(r.AggregationInterval),
+		AggregationDelay:    types.StringValue(r.AggregationDelay),
+
+		Ingest: types.BoolValue(r.Ingest),
+
+		ManagedBy: types.StringValue(r.ManagedBy),
+	}
+}
+
+// RuleSetDataTF is a segment's ruleset as read by the ruleset data source.
+type RuleSetDataTF struct {
+	Segment types.String        `tfsdk:"segment"`
+	Rules   []RuleSetRuleDataTF `tfsdk:"rules"`
+}
+
+// RuleSetRuleDataTF is a rule of a RuleSetDataTF.
+type RuleSetRuleDataTF struct {
+	Metric    types.String `tfsdk:"metric"`
+	MatchType types.String `tfsdk:"match_type"`
+
+	Drop       types.Bool     `tfsdk:"drop"`
+	KeepLabels []types.String `tfsdk:"keep_labels"`
+	DropLabels []types.String `tfsdk:"drop_labels"`
+
+	Aggregations []types.String `tfsdk:"aggregations"`
+
+	AggregationInterval types.String `tfsdk:"aggregation_interval"`
+	AggregationDelay    types.String `tfsdk:"aggregation_delay"`
+
+	Ingest types.Bool `tfsdk:"ingest"`
+
+	ManagedBy types.String `tfsdk:"managed_by"`
+}
+
+func (a AggregationRuleSet) ToDataTF(segment types.String) RuleSetDataTF {
+	rules := make([]RuleSetRuleDataTF, len(a))
+	for i, r := range a {
+		rules[i] = RuleSetRuleDataTF{
+			Metric:    types.StringValue(r.Metric),
+			MatchType: types.StringValue(r.MatchType),
+
+			Drop:       types.BoolValue(r.Drop),
+			KeepLabels: toTypesStringSlice(r.KeepLabels),
+			DropLabels: toTypesStringSlice(r.DropLabels),
+
+			Aggregations: toTypesStringSlice(r.Aggregations),
+
+			AggregationInterval: types.StringValue(r.AggregationInterval),
+			AggregationDelay:    types.StringValue(r.AggregationDelay),
+
+			Ingest: types.BoolValue(r.Ingest),
+
+			ManagedBy: types.StringValue(r.ManagedBy),
+		}
+	}
+
+	return RuleSetDataTF{
+		Segment: segment,
+		Rules:   rules,
+	}
+}
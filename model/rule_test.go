@@ -0,0 +1,144 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregationRule_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		extra     []string
+		roundTrip string
+	}{
+		{
+			name:      "known fields only",
+			input:     `{"metric":"a","match_type":"prefix","drop_labels":["pod"],"aggregations":["sum"]}`,
+			roundTrip: `{"metric":"a","match_type":"prefix","drop_labels":["pod"],"aggregations":["sum"]}`,
+		},
+		{
+			name:      "unknown fields are kept",
+			input:     `{"metric":"a","drop":true,"future_field":{"enabled": true},"other":[1,2]}`,
+			extra:     []string{"future_field", "other"},
+			roundTrip: `{"drop":true,"future_field":{"enabled":true},"metric":"a","other":[1,2]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rule AggregationRule
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &rule))
+			assert.Equal(t, "a", rule.Metric)
+			assert.Equal(t, tt.extra, rule.UnmodeledFields())
+
+			out, err := json.Marshal(rule)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.roundTrip, string(out))
+		})
+	}
+}
+
+func TestAggregationRule_MarshalJSONKnownFieldsWin(t *testing.T) {
+	rule := AggregationRule{
+		Metric: "a",
+		Extra: map[string]json.RawMessage{
+			"metric": json.RawMessage(`"b"`),
+			"drop":   json.RawMessage(`true`),
+			"future": json.RawMessage(`1`),
+		},
+	}
+
+	out, err := json.Marshal(rule)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"metric":"a","future":1}`, string(out))
+}
+
+func TestAggregationRule_WithUnmodeledFields(t *testing.T) {
+	prev := AggregationRule{
+		Metric: "a",
+		Drop:   true,
+		Extra:  map[string]json.RawMessage{"future": json.RawMessage(`1`)},
+	}
+
+	merged := AggregationRule{Metric: "a", Aggregations: []string{"sum"}}.WithUnmodeledFields(prev)
+	assert.Equal(t, AggregationRule{
+		Metric:       "a",
+		Aggregations: []string{"sum"},
+		Extra:        prev.Extra,
+	}, merged)
+	assert.Equal(t, []string{"future"}, merged.UnmodeledFields())
+	assert.True(t, merged.Equal(AggregationRule{Metric: "a", Aggregations: []string{"sum"}}), "Equal should ignore unknown fields")
+}
+
+func TestAggregationRecommendation_JSONRoundTrip(t *testing.T) {
+	input := `{"metric":"a","drop_labels":["pod"],"recommended_action":"update","usages_in_rules":1,"usages_in_queries":2,"usages_in_dashboards":3,"kept_labels":["job"],"future":"x"}`
+
+	var rec AggregationRecommendation
+	require.NoError(t, json.Unmarshal([]byte(input), &rec))
+	assert.Equal(t, "a", rec.Metric)
+	assert.Equal(t, []string{"pod"}, rec.DropLabels)
+	assert.Equal(t, "update", rec.RecommendedAction)
+	assert.Equal(t, int64(3), rec.UsagesInDashboards)
+	assert.Equal(t, []string{"job"}, rec.KeptLabels)
+	assert.Equal(t, []string{"future"}, rec.UnmodeledFields())
+
+	out, err := json.Marshal(rec)
+	require.NoError(t, err)
+	assert.JSONEq(t, input, string(out))
+}
+
+func TestAggregationRule_TFRoundTrip(t *testing.T) {
+	rules := []AggregationRule{
+		{Metric: "a", ManagedBy: managedByTF},
+		{
+			Metric:              "b",
+			MatchType:           "prefix",
+			Drop:                true,
+			KeepLabels:          []string{"job"},
+			DropLabels:          []string{},
+			Aggregations:        []string{"sum", "count"},
+			AggregationInterval: "1m",
+			AggregationDelay:    "30s",
+			Ingest:              true,
+			ManagedBy:           managedByTF,
+		},
+	}
+
+	for _, rule := range rules {
+		t.Run(rule.Metric, func(t *testing.T) {
+			assert.True(t, rule.Equal(rule.ToTF().ToAPIReq()), "rule resource round trip")
+			assert.True(t, rule.Equal(rule.ToRuleSetRuleTF().ToAPIReq()), "ruleset resource round trip")
+			assert.Equal(t, rule.Ingest, rule.ToTF().Ingest.ValueBool())
+			assert.Equal(t, rule.Ingest, rule.ToRuleSetRuleTF().Ingest.ValueBool())
+
+			rec := AggregationRecommendation{AggregationRule: rule}
+			assert.Equal(t, rule.Ingest, rec.ToTF().Ingest.ValueBool())
+		})
+	}
+}
+
+func TestAggregationRule_DataTF(t *testing.T) {
+	rules := AggregationRuleSet{
+		{Metric: "a", DropLabels: []string{"pod"}, ManagedBy: managedByTF},
+		{Metric: "b", MatchType: "prefix", Drop: true, Ingest: true},
+	}
+
+	ruleSet := rules.ToDataTF(types.StringValue("segment"))
+	assert.Equal(t, types.StringValue("segment"), ruleSet.Segment)
+	assert.Len(t, ruleSet.Rules, 2)
+	assert.Equal(t, types.StringValue("terraform"), ruleSet.Rules[0].ManagedBy)
+	assert.Equal(t, []types.String{types.StringValue("pod")}, ruleSet.Rules[0].DropLabels)
+	assert.Equal(t, types.StringValue(""), ruleSet.Rules[1].ManagedBy)
+	assert.Equal(t, types.StringValue("prefix"), ruleSet.Rules[1].MatchType)
+	assert.Equal(t, types.BoolValue(true), ruleSet.Rules[1].Ingest)
+
+	rule := rules[0].ToRuleDataTF(types.StringNull())
+	assert.Equal(t, types.StringNull(), rule.Segment)
+	assert.Equal(t, types.StringValue("a"), rule.Metric)
+	assert.Equal(t, types.StringValue("terraform"), rule.ManagedBy)
+}
@@ -43,12 +43,25 @@ type RuleSetRuleTF struct {
 
 	AggregationInterval types.String `tfsdk:"aggregation_interval"`
 	AggregationDelay    types.String `tfsdk:"aggregation_delay"`
+
+	Ingest types.Bool `tfsdk:"ingest"`
 }
 
 func (r AggregationRule) IsExactMatch() bool {
 	return r.MatchType == "" || r.MatchType == "exact"
 }
 
+// RuleKey identifies a rule within a ruleset: the API allows one rule per
+// metric and match type, with an empty match type meaning "exact".
+type RuleKey struct {
+	Metric    string
+	MatchType string
+}
+
+func (r AggregationRule) Key() RuleKey {
+	return RuleKey{Metric: r.Metric, MatchType: r.normalizedMatchType()}
+}
+
 func (r RuleSetRuleTF) ToAPIReq() AggregationRule {
 	return AggregationRule{
 		Metric:    r.Metric.ValueString(),
@@ -63,6 +76,8 @@ func (r RuleSetRuleTF) ToAPIReq() AggregationRule {
 		AggregationInterval: r.AggregationInterval.ValueString(),
 		AggregationDelay:    r.AggregationDelay.ValueString(),
 
+		Ingest: r.Ingest.ValueBool(),
+
 		ManagedBy: managedByTF,
 	}
 }
@@ -80,29 +95,35 @@ func AlignUpstreamWithState(state AggregationRuleSet, upstream AggregationRuleSe
 
 	output := make(AggregationRuleSet, 0, max(len(state), len(upstream)))
 
-	// we know there can be no duplicates by metric name, so we can use a map
-	upstreamMap := make(map[string]AggregationRule, len(upstream))
+	// the API rejects rules with the same metric and match type, but the
+	// provider must not lose rules if it ever returns some, so every key maps
+	// to the upstream rules with that key in their upstream order.
+	upstreamMap := make(map[RuleKey][]AggregationRule, len(upstream))
 	for _, rule := range upstream {
-		upstreamMap[rule.Metric] = rule
+		upstreamMap[rule.Key()] = append(upstreamMap[rule.Key()], rule)
 	}
 
 	// we iterate over the state rules, and if we find a matching rule in the
-	// upstream, we use that one and remove it from the map.
+	// upstream that hasn't been used yet, we use that one.
+	used := make(map[RuleKey]int, len(upstream))
 	for _, rule := range state {
-		if upstreamRule, ok := upstreamMap[rule.Metric]; ok {
-			output = append(output, upstreamRule)
-			delete(upstreamMap, rule.Metric)
+		key := rule.Key()
+		if upstreamRules := upstreamMap[key]; used[key] < len(upstreamRules) {
+			output = append(output, upstreamRules[used[key]])
+			used[key]++
 		}
 	}
 
 	// at this point, all rules with an equivalent in the state have been added
-	// to the output. We can now add the remaining rules from the upstream.
+	// to the output. We can now add the remaining rules from the upstream,
+	// skipping the first rules of each key, which have been used above.
 	for _, rule := range upstream {
-		// we check if the rule still exists in the map, as it may have been
-		// removed by the previous loop.
-		if _, ok := upstreamMap[rule.Metric]; ok {
-			output = append(output, rule)
+		key := rule.Key()
+		if used[key] > 0 {
+			used[key]--
+			continue
 		}
+		output = append(output, rule)
 	}
 
 	return output
@@ -127,7 +148,7 @@ func semanticallyEqualOrdering(a, b AggregationRuleSet) bool {
 		}
 
 		// Compare the non-exact rules
-		if a[aIndex].Metric != b[bIndex].Metric {
+		if a[aIndex].Key() != b[bIndex].Key() {
 			return false
 		}
 
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	fuzzMetrics    = []string{"a", "ab", "b", "ba", "abc", "cab"}
+	fuzzMatchTypes = []string{"", "exact", "prefix", "suffix"}
+)
+
+// decodeRuleSets turns fuzzer input into a state and an upstream ruleset. The
+// first byte is the number of rules in the state, and every following byte
+// describes a rule.
+func decodeRuleSets(data []byte) (state, upstream AggregationRuleSet) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	n := int(data[0])
+	for i, b := range data[1:] {
+		rule := AggregationRule{
+			Metric:    fuzzMetrics[int(b>>2)%len(fuzzMetrics)],
+			MatchType: fuzzMatchTypes[b&3],
+			Drop:      b&0x80 != 0,
+		}
+		if i < n {
+			state = append(state, rule)
+		} else {
+			upstream = append(upstream, rule)
+		}
+	}
+	return state, upstream
+}
+
+func FuzzAlignUpstreamWithState(f *testing.F) {
+	f.Add([]byte{0})
+	f.Add([]byte{2, 0x00, 0x05, 0x05, 0x00})
+	f.Add([]byte{3, 0x02, 0x06, 0x0b, 0x0b, 0x02, 0x06})
+	f.Add([]byte{2, 0x02, 0x03, 0x03, 0x02})
+	f.Add([]byte{1, 0x00, 0x02, 0x00, 0x06})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		state, upstream := decodeRuleSets(data)
+		output := AlignUpstreamWithState(state, upstream)
+
+		requireAlignmentProperties(t, state, upstream, output)
+	})
+}
+
+// requireAlignmentProperties checks that output is a reordering of upstream
+// that neither changes which rule applies to a metric nor, when that is
+// possible, deviates from the order of the state.
+func requireAlignmentProperties(t *testing.T, state, upstream, output AggregationRuleSet) {
+	t.Helper()
+
+	require.ElementsMatch(t, upstream, output, "output must be a permutation of upstream")
+
+	isNonExact := func(r AggregationRule) bool { return !r.IsExactMatch() }
+	require.Equal(t, filterRules(upstream, isNonExact), filterRules(output, isNonExact), "relative order of non-exact rules must be preserved")
+
+	for _, metric := range fuzzMetrics {
+		for _, probe := range []string{metric, metric + "_x", "x_" + metric} {
+			want, wantOK := upstream.Match(probe)
+			got, gotOK := output.Match(probe)
+			require.Equal(t, wantOK, gotOK, "whether a rule applies to %q changed", probe)
+			require.Equal(t, want, got, "the rule that applies to %q changed", probe)
+		}
+	}
+
+	if !semanticallyEqualOrdering(state, upstream) {
+		require.Equal(t, upstream, output, "upstream must be kept as is if it can't follow the state's order")
+		return
+	}
+
+	// The rules that are also in the state come first, in the state's order.
+	remaining := make(map[RuleKey]int)
+	for _, rule := range upstream {
+		remaining[rule.Key()]++
+	}
+	expected := []RuleKey{}
+	for _, rule := range state {
+		if remaining[rule.Key()] > 0 {
+			remaining[rule.Key()]--
+			expected = append(expected, rule.Key())
+		}
+	}
+	actual := make([]RuleKey, len(expected))
+	for i, rule := range output[:len(expected)] {
+		actual[i] = rule.Key()
+	}
+	require.Equal(t, expected, actual, "rules must follow the state's order")
+}
+
+func filterRules(rules AggregationRuleSet, f func(r AggregationRule) bool) AggregationRuleSet {
+	output := AggregationRuleSet{}
+	for _, rule := range rules {
+		if f(rule) {
+			output = append(output, rule)
+		}
+	}
+	return slices.Clip(output)
+}
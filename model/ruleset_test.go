@@ -0,0 +1,181 @@
+package model
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignUpstreamWithState(t *testing.T) {
+	t.Run("it re-orders exact matches", func(t *testing.T) {
+
+		state := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "b", MatchType: "exact"},
+			{Metric: "d", MatchType: "exact"},
+		}
+
+		config := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "b", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "d", MatchType: "exact"},
+		}
+
+		output := AlignUpstreamWithState(state, config)
+		requireAlignmentProperties(t, state, config, output)
+	})
+
+	t.Run("it keeps new items at the end", func(t *testing.T) {
+		state := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "b", MatchType: "exact"},
+			{Metric: "d", MatchType: "exact"},
+		}
+
+		config := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "b", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "d", MatchType: "exact"},
+			{Metric: "e", MatchType: "exact"},
+			{Metric: "f", MatchType: "exact"},
+		}
+
+		output := AlignUpstreamWithState(state, config)
+		requireAlignmentProperties(t, state, config, output)
+	})
+
+	t.Run("it skips missing items", func(t *testing.T) {
+		state := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "b", MatchType: "exact"},
+			{Metric: "d", MatchType: "exact"},
+		}
+
+		config := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "b", MatchType: "exact"},
+		}
+
+		output := AlignUpstreamWithState(state, config)
+		requireAlignmentProperties(t, state, config, output)
+	})
+
+	t.Run("it preserves order for non-exact matches", func(t *testing.T) {
+		state := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "b", MatchType: "prefix"},
+			{Metric: "d", MatchType: "prefix"},
+		}
+
+		config := AggregationRuleSet{
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "b", MatchType: "prefix"},
+			{Metric: "d", MatchType: "prefix"},
+		}
+
+		output := AlignUpstreamWithState(state, config)
+		requireAlignmentProperties(t, state, config, output)
+	})
+
+	t.Run("it gives up if non-exact matches aren't ordered the same", func(t *testing.T) {
+		state := AggregationRuleSet{
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "b", MatchType: "prefix"},
+			{Metric: "d", MatchType: "prefix"},
+		}
+
+		config := AggregationRuleSet{
+			{Metric: "c", MatchType: "exact"},
+			{Metric: "a", MatchType: "exact"},
+			{Metric: "d", MatchType: "prefix"},
+			{Metric: "b", MatchType: "prefix"},
+		}
+
+		output := AlignUpstreamWithState(state, config)
+		requireAlignmentProperties(t, state, config, output)
+	})
+}
+
+func TestAlignUpstreamWithStateSameMetricDifferentMatchTypes(t *testing.T) {
+	t.Run("it keeps every rule sharing a metric", func(t *testing.T) {
+		state := AggregationRuleSet{
+			{Metric: "http_requests", MatchType: "prefix"},
+			{Metric: "http_requests"},
+			{Metric: "up", MatchType: "exact"},
+		}
+
+		upstream := AggregationRuleSet{
+			{Metric: "up", MatchType: "exact"},
+			{Metric: "http_requests", MatchType: "exact", Drop: true},
+			{Metric: "http_requests", MatchType: "prefix"},
+		}
+
+		output := AlignUpstreamWithState(state, upstream)
+		require.Equal(t, AggregationRuleSet{upstream[2], upstream[1], upstream[0]}, output)
+		requireAlignmentProperties(t, state, upstream, output)
+	})
+
+	t.Run("it tells prefix and suffix rules for the same metric apart", func(t *testing.T) {
+		state := AggregationRuleSet{
+			{Metric: "total", MatchType: "prefix"},
+			{Metric: "total", MatchType: "suffix"},
+		}
+
+		upstream := AggregationRuleSet{
+			{Metric: "total", MatchType: "suffix"},
+			{Metric: "total", MatchType: "prefix"},
+		}
+
+		output := AlignUpstreamWithState(state, upstream)
+		require.Equal(t, upstream, output, "reordering non-exact rules would change which rule applies")
+		requireAlignmentProperties(t, state, upstream, output)
+	})
+}
+
+// TestAlignUpstreamWithStateProperties checks the properties of alignment on
+// random pairs of rulesets without duplicates, as the API returns them.
+// FuzzAlignUpstreamWithState covers arbitrary rulesets.
+func TestAlignUpstreamWithStateProperties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	randomRuleSet := func() AggregationRuleSet {
+		var rules AggregationRuleSet
+		seen := map[RuleKey]bool{}
+		for n := rnd.Intn(8); n > 0; n-- {
+			rule := AggregationRule{
+				Metric:    fuzzMetrics[rnd.Intn(len(fuzzMetrics))],
+				MatchType: fuzzMatchTypes[rnd.Intn(len(fuzzMatchTypes))],
+				Drop:      rnd.Intn(2) == 0,
+			}
+			if !seen[rule.Key()] {
+				seen[rule.Key()] = true
+				rules = append(rules, rule)
+			}
+		}
+		return rules
+	}
+
+	for i := 0; i < 2000; i++ {
+		state := randomRuleSet()
+		upstream := randomRuleSet()
+		if rnd.Intn(2) == 0 {
+			// Make upstream a shuffled copy of the state, which is the
+			// common case of the API returning the rules in another order.
+			upstream = slices.Clone(state)
+			rnd.Shuffle(len(upstream), func(i, j int) { upstream[i], upstream[j] = upstream[j], upstream[i] })
+		}
+
+		output := AlignUpstreamWithState(state, upstream)
+		requireAlignmentProperties(t, state, upstream, output)
+	}
+}
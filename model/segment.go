@@ -63,3 +63,21 @@ func (e SegmentTF) ToAPIReq() Segment {
 
 	return segment
 }
+
+type SegmentListTF struct {
+	Name     types.String `tfsdk:"name"`
+	Selector types.String `tfsdk:"selector"`
+	Segments []SegmentTF  `tfsdk:"segments"`
+}
+
+// Matches reports whether the segment passes the list's filters. Filters that
+// aren't set match every segment.
+func (tf SegmentListTF) Matches(s Segment) bool {
+	if !tf.Name.IsNull() && tf.Name.ValueString() != s.Name {
+		return false
+	}
+	if !tf.Selector.IsNull() && tf.Selector.ValueString() != s.Selector {
+		return false
+	}
+	return true
+}
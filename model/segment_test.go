@@ -186,3 +186,13 @@ func TestSegmentTF_ToAPIReq(t *testing.T) {
 		})
 	}
 }
+
+func TestSegmentListTF_Matches(t *testing.T) {
+	segment := Segment{Name: "a", Selector: `{namespace="a"}`}
+
+	assert.True(t, SegmentListTF{Name: types.StringNull(), Selector: types.StringNull()}.Matches(segment))
+	assert.True(t, SegmentListTF{Name: types.StringValue("a"), Selector: types.StringNull()}.Matches(segment))
+	assert.False(t, SegmentListTF{Name: types.StringValue("b"), Selector: types.StringNull()}.Matches(segment))
+	assert.True(t, SegmentListTF{Name: types.StringValue("a"), Selector: types.StringValue(`{namespace="a"}`)}.Matches(segment))
+	assert.False(t, SegmentListTF{Name: types.StringValue("a"), Selector: types.StringValue(`{namespace="b"}`)}.Matches(segment))
+}
@@ -7,7 +7,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/internal/model"
+	"github.com/hashicorp/terraform-provider-grafana-adaptive-metrics/model"
 )
 
 func main() {
@@ -62,6 +62,9 @@ func main() {
 		if rule.AggregationDelay != "" {
 			fmt.Printf("  aggregation_delay    = \"%s\"\n", rule.AggregationDelay)
 		}
+		if rule.Ingest {
+			fmt.Printf("  ingest               = %t\n", rule.Ingest)
+		}
 		fmt.Println("}")
 		fmt.Println()
 	}